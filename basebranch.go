@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/git"
+	"strings"
+)
+
+// resolveBaseBranch returns start point for a new branch of the issue type.
+// Base branch is taken from -from flag or configuration, fetched from its remote
+// and resolved to the latest matching branch when it is a glob pattern like 'origin/release/*'.
+// Empty result means that the branch is created from the current HEAD.
+func resolveBaseBranch(ctx context.Context, issueType string) (string, error) {
+	baseBranch := config.Options.FromBranch
+	if baseBranch == "" {
+		baseBranch = config.Options.BaseBranch.ByIssueType[issueType]
+	}
+	if baseBranch == "" {
+		baseBranch = config.Options.BaseBranch.Default
+	}
+	if baseBranch == "" {
+		return "", nil
+	}
+
+	fetchBaseBranch(ctx, baseBranch)
+
+	if !strings.ContainsAny(baseBranch, "*?[") {
+		return baseBranch, nil
+	}
+
+	latestBranch, err := git.FindLatestRef(ctx, baseBranch)
+	if err != nil {
+		return "", err
+	}
+	if latestBranch == "" {
+		return "", fmt.Errorf("There are no branches matching base branch pattern '%s'", baseBranch)
+	}
+
+	return latestBranch, nil
+}
+
+// fetchBaseBranch updates remote-tracking base branch, failures are reported
+// but do not prevent creating a branch from the last fetched state
+func fetchBaseBranch(ctx context.Context, baseBranch string) {
+	remotes, err := git.GetRemotes(ctx)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	for _, remote := range remotes {
+		if !strings.HasPrefix(baseBranch, remote+"/") {
+			continue
+		}
+
+		remoteBranch := strings.TrimPrefix(baseBranch, remote+"/")
+		var output []byte
+		if strings.ContainsAny(remoteBranch, "*?[") {
+			output, err = git.Fetch(ctx, remote)
+		} else {
+			output, err = git.Fetch(ctx, remote, remoteBranch)
+		}
+		if err != nil {
+			printErrorToConsole(err)
+			printInfoToConsole(strings.TrimSpace(string(output)))
+		}
+		return
+	}
+}
+
+// getComparisonBaseBranch returns branch passed with -base flag, configured default base branch
+// or default branch of origin remote
+func getComparisonBaseBranch(ctx context.Context, baseBranch string) (string, error) {
+	if baseBranch == "" {
+		baseBranch = config.Options.BaseBranch.Default
+	}
+	if baseBranch == "" {
+		remoteHeadBranch, err := git.GetRemoteHeadBranch(ctx, "origin")
+		if err != nil {
+			return "", errors.New("Base branch is not configured and default branch of origin is unknown, use -base")
+		}
+		baseBranch = remoteHeadBranch
+	}
+
+	if !strings.ContainsAny(baseBranch, "*?[") {
+		return baseBranch, nil
+	}
+
+	return git.FindLatestRef(ctx, baseBranch)
+}
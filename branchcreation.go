@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/git"
+	"got/pkg/jira"
+	"path"
+	"sort"
+	"strings"
+)
+
+// updateIssueForNewBranch adds configured labels and components to Jira issue of a new branch and sets its fields
+func updateIssueForNewBranch(ctx context.Context, issueKey string) error {
+	options := config.Options.BranchCreation
+	if (options.DisableRepoLabel || options.RepoLabel == "") && len(options.Labels) == 0 &&
+		len(options.Components) == 0 && len(options.Fields) == 0 && len(options.Rules) == 0 {
+		return nil
+	}
+
+	repositoryURL, err := git.GetRepositoryURL(ctx, repository, config.Options.Git.Remote)
+	if err != nil {
+		return err
+	}
+
+	update, err := getBranchCreationIssueUpdate(options, repositoryURL)
+	if err != nil {
+		return err
+	}
+	if len(update.Labels) == 0 && len(update.Components) == 0 && len(update.Fields) == 0 {
+		return nil
+	}
+
+	err = jiraClient.UpdateIssue(ctx, issueKey, update)
+	if err != nil {
+		return err
+	}
+
+	if len(update.Labels) > 0 {
+		printInfoToConsole(fmt.Sprintf("Added labels to the Jira issue: '%s'", strings.Join(update.Labels, ", ")))
+	}
+	if len(update.Components) > 0 {
+		printInfoToConsole(fmt.Sprintf("Added components to the Jira issue: '%s'", strings.Join(update.Components, ", ")))
+	}
+	if len(update.Fields) > 0 {
+		var fieldNames []string
+		for fieldName := range update.Fields {
+			fieldNames = append(fieldNames, fieldName)
+		}
+		sort.Strings(fieldNames)
+		printInfoToConsole(fmt.Sprintf("Updated fields of the Jira issue: '%s'", strings.Join(fieldNames, ", ")))
+	}
+	return nil
+}
+
+// getBranchCreationIssueUpdate returns labels, components and fields configured for the repository
+// with placeholders replaced by parts of the repository URL
+func getBranchCreationIssueUpdate(options config.BranchCreationOptions, repositoryURL git.RepositoryURL) (jira.IssueUpdate, error) {
+	var labelTemplates []string
+	if !options.DisableRepoLabel && options.RepoLabel != "" {
+		labelTemplates = append(labelTemplates, options.RepoLabel)
+	}
+	labelTemplates = append(labelTemplates, options.Labels...)
+	components := append([]string{}, options.Components...)
+	fields := map[string]interface{}{}
+	for fieldName, value := range options.Fields {
+		fields[fieldName] = value
+	}
+
+	for _, rule := range options.Rules {
+		matched, err := path.Match(rule.RepoPath, repositoryURL.Path())
+		if err != nil {
+			return jira.IssueUpdate{}, fmt.Errorf("Invalid repository path pattern '%s': %s", rule.RepoPath, err.Error())
+		}
+		if !matched {
+			continue
+		}
+
+		labelTemplates = append(labelTemplates, rule.Labels...)
+		components = append(components, rule.Components...)
+		for fieldName, value := range rule.Fields {
+			fields[fieldName] = value
+		}
+	}
+
+	var update jira.IssueUpdate
+	for _, labelTemplate := range labelTemplates {
+		label := repositoryURL.ExpandTemplate(labelTemplate)
+		if label != "" && !stringInSlice(label, update.Labels) {
+			update.Labels = append(update.Labels, label)
+		}
+	}
+	for _, componentTemplate := range components {
+		component := repositoryURL.ExpandTemplate(componentTemplate)
+		if component != "" && !stringInSlice(component, update.Components) {
+			update.Components = append(update.Components, component)
+		}
+	}
+	if len(fields) > 0 {
+		update.Fields = map[string]interface{}{}
+		for fieldName, value := range fields {
+			update.Fields[fieldName] = expandFieldValueTemplates(value, repositoryURL)
+		}
+	}
+
+	return update, nil
+}
+
+// expandFieldValueTemplates replaces placeholders in strings of JSON field value
+func expandFieldValueTemplates(value interface{}, repositoryURL git.RepositoryURL) interface{} {
+	switch typedValue := value.(type) {
+	case string:
+		return repositoryURL.ExpandTemplate(typedValue)
+	case []interface{}:
+		expandedValues := make([]interface{}, len(typedValue))
+		for i, item := range typedValue {
+			expandedValues[i] = expandFieldValueTemplates(item, repositoryURL)
+		}
+		return expandedValues
+	case map[string]interface{}:
+		expandedValues := map[string]interface{}{}
+		for key, item := range typedValue {
+			expandedValues[key] = expandFieldValueTemplates(item, repositoryURL)
+		}
+		return expandedValues
+	default:
+		return value
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"got/pkg/config"
+	"got/pkg/git"
+	"reflect"
+	"testing"
+)
+
+func TestGetBranchCreationIssueUpdate(t *testing.T) {
+	repositoryURL := git.RepositoryURL{Host: "github.com", Owner: "payments", Name: "billing-api"}
+	options := config.BranchCreationOptions{
+		RepoLabel:  "repo-{owner}-{repo}",
+		Labels:     []string{"got", "repo-{owner}-{repo}"},
+		Components: []string{"{repo}"},
+		Fields:     map[string]interface{}{"customfield_10002": "{host}"},
+		Rules: []config.BranchCreationRule{
+			{
+				RepoPath:   "payments/*",
+				Labels:     []string{"team-payments"},
+				Components: []string{"Billing"},
+				Fields:     map[string]interface{}{"customfield_10001": map[string]interface{}{"value": "Payments {owner}"}},
+			},
+			{RepoPath: "search/*", Labels: []string{"team-search"}},
+		},
+	}
+
+	update, err := getBranchCreationIssueUpdate(options, repositoryURL)
+	if err != nil {
+		t.Fatalf("getBranchCreationIssueUpdate returned error %+v", err.Error())
+	}
+	if want := []string{"repo-payments-billing-api", "got", "team-payments"}; !reflect.DeepEqual(update.Labels, want) {
+		t.Errorf("getBranchCreationIssueUpdate labels %+v, want %+v", update.Labels, want)
+	}
+	if want := []string{"billing-api", "Billing"}; !reflect.DeepEqual(update.Components, want) {
+		t.Errorf("getBranchCreationIssueUpdate components %+v, want %+v", update.Components, want)
+	}
+	wantFields := map[string]interface{}{
+		"customfield_10001": map[string]interface{}{"value": "Payments payments"},
+		"customfield_10002": "github.com",
+	}
+	if !reflect.DeepEqual(update.Fields, wantFields) {
+		t.Errorf("getBranchCreationIssueUpdate fields %+v, want %+v", update.Fields, wantFields)
+	}
+
+	options = config.BranchCreationOptions{RepoLabel: "repo-{repo}", DisableRepoLabel: true}
+	update, err = getBranchCreationIssueUpdate(options, repositoryURL)
+	if err != nil || len(update.Labels) != 0 || update.Fields != nil {
+		t.Errorf("getBranchCreationIssueUpdate with disabled repo label returned %+v, %+v", update, err)
+	}
+
+	options = config.BranchCreationOptions{Rules: []config.BranchCreationRule{{RepoPath: "["}}}
+	if _, err = getBranchCreationIssueUpdate(options, repositoryURL); err == nil {
+		t.Errorf("getBranchCreationIssueUpdate with invalid pattern did not return error")
+	}
+}
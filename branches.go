@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/git"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const maxSummaryLength = 40
+
+func printBranches(ctx context.Context) {
+	branches, err := git.ListBranchesInfo(ctx)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	var issueBranches []git.BranchInfo
+	var issueKeys []string
+	for _, branch := range branches {
+		branchIssueKeys := branchNamer.GetIssueKeysFromBranchName(branch.Name)
+		if len(branchIssueKeys) == 0 {
+			continue
+		}
+		issueBranches = append(issueBranches, branch)
+		issueKeys = append(issueKeys, branchIssueKeys...)
+	}
+
+	if len(issueBranches) == 0 {
+		printInfoToConsole("There are no branches linked to Jira issues")
+		return
+	}
+
+	baseBranch, err := getComparisonBaseBranch(ctx, config.Options.Branches.BaseBranch)
+	if err != nil {
+		printErrorToConsole(err)
+	}
+
+	var mergedBranchNames []string
+	if baseBranch != "" {
+		mergedBranchNames, err = git.ListMergedBranches(ctx, baseBranch, false)
+		if err != nil {
+			printErrorToConsole(err)
+		}
+	}
+
+	issues := fetchIssues(ctx, issueKeys)
+	staleDuration := time.Duration(config.Options.Branches.StaleDays) * 24 * time.Hour
+
+	if baseBranch != "" {
+		printInfoToConsole(fmt.Sprintf("Base branch: %s", baseBranch))
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "BRANCH\tISSUE\tSTATUS\tASSIGNEE\tSUMMARY\tLAST COMMIT\tUPSTREAM\tBASE\tWARNINGS")
+	for _, branch := range issueBranches {
+		upstreamComparison := compareBranches(ctx, branch.Name, branch.Upstream)
+		baseComparison := compareBranches(ctx, branch.Name, baseBranch)
+		merged := stringInSlice(branch.Name, mergedBranchNames)
+
+		for i, issueKey := range branchNamer.GetIssueKeysFromBranchName(branch.Name) {
+			branchName, lastCommitDate := branch.Name, branch.LastCommitDate.Format("2006-01-02")
+			if i > 0 {
+				branchName, lastCommitDate, upstreamComparison, baseComparison = "", "", "", ""
+			}
+
+			issue, ok := issues[issueKey]
+			if !ok {
+				fmt.Fprintf(writer, "%s\t%s\t\t\t\t%s\t%s\t%s\tissue not found\n",
+					branchName, issueKey, lastCommitDate, upstreamComparison, baseComparison)
+				continue
+			}
+
+			var warnings []string
+			if issue.IsDone() && baseBranch != "" && !merged {
+				warnings = append(warnings, "issue is done but branch is not merged")
+			}
+			if issue.IsDone() && merged {
+				warnings = append(warnings, "issue is done, branch can be pruned")
+			}
+			if issue.IsInProgress() && time.Since(branch.LastCommitDate) > staleDuration {
+				warnings = append(warnings, fmt.Sprintf(
+					"issue is in progress but there are no commits for %d days",
+					int(time.Since(branch.LastCommitDate).Hours()/24),
+				))
+			}
+
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				branchName, issueKey, issue.Fields.Status.Name, issue.GetAssigneeName(),
+				truncateString(issue.Fields.Summary, maxSummaryLength), lastCommitDate,
+				upstreamComparison, baseComparison, strings.Join(warnings, "; "))
+		}
+	}
+	writer.Flush()
+}
+
+// compareBranches returns numbers of commits ahead and behind the other branch formatted as '+1 -2'
+func compareBranches(ctx context.Context, branchName string, otherBranchName string) string {
+	if otherBranchName == "" {
+		return "-"
+	}
+
+	ahead, behind, err := git.CountAheadBehind(ctx, branchName, otherBranchName)
+	if err != nil {
+		return "?"
+	}
+
+	return fmt.Sprintf("+%d -%d", ahead, behind)
+}
+
+func truncateString(value string, maxLength int) string {
+	runes := []rune(value)
+	if len(runes) <= maxLength {
+		return value
+	}
+	return string(runes[:maxLength-3]) + "..."
+}
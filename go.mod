@@ -1,3 +1,8 @@
 module got
 
 go 1.15
+
+require (
+	github.com/go-git/go-billy/v5 v5.0.0
+	github.com/go-git/go-git/v5 v5.2.0
+)
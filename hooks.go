@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/git"
+	"got/pkg/jira"
+	"got/pkg/timesheet"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const hookScriptMarker = "# Installed by got"
+
+const hookScriptTemplate = `#!/bin/sh
+# Installed by got, reinstall with 'got hooks install'
+command -v got >/dev/null 2>&1 || exit 0
+exec got hook %s "$@"
+`
+
+var installedHooks = []string{"post-checkout", "prepare-commit-msg", "pre-push"}
+
+// blockingHooks abort git operation when got reports an error
+var blockingHooks = map[string]bool{"pre-push": true}
+
+// prePushLinkTimeout limits linking of Jira issues to pushed branches, so that slow Jira does not delay pushes
+const prePushLinkTimeout = 5 * time.Second
+
+// isHookRun checks if got runs as a git hook
+func isHookRun() bool {
+	return len(os.Args) > 1 && os.Args[1] == "hook"
+}
+
+// isBlockingHookRun checks if got runs as a blocking hook, which aborts git operation on configuration errors as well
+func isBlockingHookRun() bool {
+	return isHookRun() && len(os.Args) > 2 && blockingHooks[os.Args[2]]
+}
+
+func installHooks(ctx context.Context) {
+	hooksDirectory, err := git.GetHooksDirectory(ctx)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	err = os.MkdirAll(hooksDirectory, 0755)
+	if err != nil {
+		printErrorToConsole(fmt.Errorf("Failed to create hooks directory '%s': %s", hooksDirectory, err.Error()))
+		return
+	}
+
+	for _, hookName := range installedHooks {
+		hookPath := filepath.Join(hooksDirectory, hookName)
+
+		existingScript, err := ioutil.ReadFile(hookPath)
+		if err == nil && !strings.Contains(string(existingScript), hookScriptMarker) {
+			printErrorToConsole(fmt.Errorf("Hook '%s' already exists and was not installed by got, skipping it", hookPath))
+			continue
+		}
+
+		err = ioutil.WriteFile(hookPath, []byte(fmt.Sprintf(hookScriptTemplate, hookName)), 0755)
+		if err == nil {
+			err = os.Chmod(hookPath, 0755)
+		}
+		if err != nil {
+			printErrorToConsole(fmt.Errorf("Failed to install hook '%s': %s", hookPath, err.Error()))
+			continue
+		}
+
+		printInfoToConsole(fmt.Sprintf("Installed %s hook to '%s'", hookName, hookPath))
+	}
+}
+
+func runHook(ctx context.Context) {
+	var err error
+	switch config.Options.Hook.Name {
+	case "post-checkout":
+		err = runPostCheckoutHook(ctx, config.Options.Hook.Args)
+	case "prepare-commit-msg":
+		err = runPrepareCommitMsgHook(ctx, config.Options.Hook.Args)
+	case "pre-push":
+		err = runPrePushHook(ctx, config.Options.Hook.Args, os.Stdin)
+	default:
+		err = fmt.Errorf("Unknown hook '%s'", config.Options.Hook.Name)
+	}
+
+	if err != nil {
+		printErrorToConsole(err)
+		if blockingHooks[config.Options.Hook.Name] {
+			os.Exit(1)
+		}
+	}
+}
+
+// runPostCheckoutHook records branch switches to the timesheet journal.
+// Git passes previous HEAD, new HEAD and a flag that is 1 for branch checkouts.
+func runPostCheckoutHook(ctx context.Context, args []string) error {
+	if len(args) != 3 {
+		return errors.New("post-checkout hook expects 3 arguments")
+	}
+
+	if args[2] != "1" {
+		return nil
+	}
+
+	// detached HEAD is recorded with empty branch name, so that it ends the session of the previous branch
+	branchName, err := repository.CurrentBranch(ctx)
+	if err != nil {
+		return err
+	}
+
+	repositoryRoot, err := git.GetRepositoryRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	journal, err := timesheet.DefaultJournal()
+	if err != nil {
+		return err
+	}
+
+	return journal.AddEntry(timesheet.Entry{
+		Time:       time.Now(),
+		Repository: repositoryRoot,
+		Branch:     branchName,
+	})
+}
+
+// runPrepareCommitMsgHook adds issue keys of the current branch to the commit message.
+// Git passes message file path, message source and commit sha depending on the source.
+func runPrepareCommitMsgHook(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("prepare-commit-msg hook expects commit message file path")
+	}
+
+	if len(args) > 1 && (args[1] == "merge" || args[1] == "squash") {
+		return nil
+	}
+
+	branchName, err := repository.CurrentBranch(ctx)
+	if err != nil || branchName == "" {
+		return err
+	}
+
+	issueKeys := branchNamer.GetIssueKeysFromBranchName(branchName)
+	if len(issueKeys) == 0 {
+		return nil
+	}
+
+	messageFilePath := args[0]
+	content, err := ioutil.ReadFile(messageFilePath)
+	if err != nil {
+		return fmt.Errorf("Failed to read commit message file '%s': %s", messageFilePath, err.Error())
+	}
+
+	message := string(content)
+	if git.IsFixupCommitMessage(message) || branchNamer.CommitMessageContainsIssueKey(message) {
+		return nil
+	}
+
+	message = git.AddIssueKeysToCommitMessage(message, issueKeys, config.Options.CommitMessage.IssueKeysPosition)
+	err = ioutil.WriteFile(messageFilePath, []byte(message), 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to write commit message file '%s': %s", messageFilePath, err.Error())
+	}
+
+	return nil
+}
+
+// runPrePushHook checks that pushed branches are linked to existing and not resolved Jira issues
+// and links the issues to the pushed branches. Git passes remote name and URL as arguments
+// and pushed refs to stdin as lines '<local ref> <local sha> <remote ref> <remote sha>'.
+func runPrePushHook(ctx context.Context, args []string, input io.Reader) error {
+	var problems []string
+	var branchNames []string
+	branchesIssueKeys := map[string][]string{}
+	remoteBranchNames := map[string]string{}
+
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || !strings.HasPrefix(fields[0], "refs/heads/") || strings.Trim(fields[1], "0") == "" {
+			continue
+		}
+
+		branchName := strings.TrimPrefix(fields[0], "refs/heads/")
+		if git.MatchBranchName(branchName, config.GetPrePushAllowedBranches()) {
+			continue
+		}
+
+		issueKeyPatterns := config.GetPrePushIssueKeyPatterns()
+		issueKeys, err := git.FindIssueKeysByPatterns(branchName, issueKeyPatterns)
+		if err != nil {
+			return err
+		}
+
+		if len(issueKeys) == 0 {
+			problems = append(problems, fmt.Sprintf(
+				"branch '%s' does not contain issue keys matching '%s'", branchName, strings.Join(issueKeyPatterns, "', '"),
+			))
+			continue
+		}
+
+		branchNames = append(branchNames, branchName)
+		branchesIssueKeys[branchName] = issueKeys
+		remoteBranchNames[branchName] = strings.TrimPrefix(fields[2], "refs/heads/")
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Failed to read pushed refs: %s", err.Error())
+	}
+
+	if config.Options.PrePush.CheckIssueStatus && len(branchNames) > 0 {
+		issueStatusProblems, err := validatePushedBranchesIssues(ctx, branchNames, branchesIssueKeys)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, issueStatusProblems...)
+	}
+
+	if len(problems) == 0 {
+		// links are not required for the push, so failures are reported without blocking it.
+		// Pushes to URLs instead of named remotes get the URL as both arguments and are not linked.
+		if len(args) == 2 && args[0] != args[1] {
+			err := linkIssuesToPushedBranches(ctx, args[0], branchNames, remoteBranchNames, branchesIssueKeys)
+			if err != nil {
+				printErrorToConsole(err)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf(
+		"Push blocked:\n  - %s\nRename the branch with 'got -lj XXXX' or skip the check with 'git push --no-verify'",
+		strings.Join(problems, "\n  - "),
+	)
+}
+
+// linkIssuesToPushedBranches links Jira issues to pushed branches concurrently within prePushLinkTimeout.
+// The hook runs before the push, so branches are linked even when the remote rejects the push afterwards.
+func linkIssuesToPushedBranches(
+	ctx context.Context, remote string, branchNames []string,
+	remoteBranchNames map[string]string, branchesIssueKeys map[string][]string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, prePushLinkTimeout)
+	defer cancel()
+
+	var waitGroup sync.WaitGroup
+	var mutex sync.Mutex
+	var errs taskErrors
+	for _, branchName := range branchNames {
+		branchName := branchName
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+
+			err := linkIssuesToBranch(ctx, remote, remoteBranchNames[branchName], branchesIssueKeys[branchName])
+			if err != nil {
+				mutex.Lock()
+				errs = append(errs, err)
+				mutex.Unlock()
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validatePushedBranchesIssues(ctx context.Context, branchNames []string, branchesIssueKeys map[string][]string) ([]string, error) {
+	var issueKeys []string
+	for _, branchName := range branchNames {
+		issueKeys = append(issueKeys, branchesIssueKeys[branchName]...)
+	}
+
+	foundIssues, err := jiraClient.GetIssues(ctx, issueKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := map[string]jira.Issue{}
+	for _, issue := range foundIssues {
+		issues[issue.Key] = issue
+	}
+
+	var problems []string
+	for _, branchName := range branchNames {
+		for _, issueKey := range branchesIssueKeys[branchName] {
+			issue, ok := issues[issueKey]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("branch '%s': Jira issue %s not found", branchName, issueKey))
+				continue
+			}
+
+			if issue.IsDone() {
+				problems = append(problems, fmt.Sprintf(
+					"branch '%s': Jira issue %s is already in '%s' status", branchName, issueKey, issue.Fields.Status.Name,
+				))
+			}
+		}
+	}
+
+	return problems, nil
+}
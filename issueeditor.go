@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/jira"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// issueScissorsLine separates issue draft from the help ignored by got, like 'git commit --cleanup=scissors' does
+const issueScissorsLine = "# ------------------------ >8 ------------------------"
+
+const issueDraftHelp = `# Do not modify or remove the line above, everything below it is ignored.
+# Enter the issue summary on the first line and its description in Markdown
+# after an empty line. An empty summary aborts the issue creation.
+#
+# Issue type and components can be set with lines at the end of the description:
+#
+# Type: Bug
+# Components: API, Billing
+#
+`
+
+// issueDraftTrailerRegexp matches 'Type:' and 'Components:' lines at the end of issue draft
+var issueDraftTrailerRegexp = regexp.MustCompile(`(?i)^(type|components):\s*(.*?)\s*$`)
+
+// newIssueFromTemplate returns issue with the summary and fields and description of the template
+func newIssueFromTemplate(summary string, issueTemplate config.IssueTemplate) jira.NewIssue {
+	newIssue := jira.NewIssue{
+		Summary:    summary,
+		IssueType:  issueTemplate.IssueType,
+		Components: issueTemplate.Components,
+		Labels:     issueTemplate.Labels,
+		Priority:   issueTemplate.Priority,
+	}
+	if issueTemplate.Description != "" {
+		document := jira.MarkdownToADF(issueTemplate.Description)
+		newIssue.Description = &document
+	}
+
+	return newIssue
+}
+
+// composeIssue opens the editor with issue draft pre-filled from the template and returns the issue composed in it.
+// Labels and priority are taken from the template, type and components are taken from it when the draft does not set them.
+// The draft file is kept until the issue is created, so its path is returned as well, also with errors of the editor.
+func composeIssue(ctx context.Context, summary string, issueTemplate config.IssueTemplate) (jira.NewIssue, string, error) {
+	project, projectErr := jiraClient.GetProject(ctx)
+
+	draftFile, err := ioutil.TempFile("", "got-issue-*.md")
+	if err != nil {
+		return jira.NewIssue{}, "", fmt.Errorf("Failed to create issue draft file. Error: '%s'", err.Error())
+	}
+	draftPath := draftFile.Name()
+	_, err = draftFile.WriteString(getIssueDraftTemplate(summary, issueTemplate, project, projectErr))
+	draftFile.Close()
+	if err != nil {
+		os.Remove(draftPath)
+		return jira.NewIssue{}, "", fmt.Errorf("Failed to write issue draft file. Error: '%s'", err.Error())
+	}
+
+	// the draft is kept when the editor fails, so that the composed text is not lost
+	err = runEditor(draftPath)
+	if err != nil {
+		return jira.NewIssue{}, draftPath, err
+	}
+
+	draft, err := ioutil.ReadFile(draftPath)
+	if err != nil {
+		return jira.NewIssue{}, draftPath, fmt.Errorf("Failed to read issue draft file. Error: '%s'", err.Error())
+	}
+
+	newIssue := parseIssueDraft(string(draft))
+	if newIssue.Summary == "" {
+		os.Remove(draftPath)
+		return jira.NewIssue{}, "", errors.New("Aborting Jira issue creation due to empty summary")
+	}
+
+	if newIssue.IssueType == "" {
+		newIssue.IssueType = issueTemplate.IssueType
+	}
+	if len(newIssue.Components) == 0 {
+		newIssue.Components = issueTemplate.Components
+	}
+	newIssue.Labels = issueTemplate.Labels
+	newIssue.Priority = issueTemplate.Priority
+
+	// issue type and components are matched ignoring case, Jira requires exact names
+	for _, issueType := range project.IssueTypes {
+		if strings.EqualFold(issueType.Name, newIssue.IssueType) {
+			newIssue.IssueType = issueType.Name
+		}
+	}
+	for i, componentName := range newIssue.Components {
+		for _, component := range project.Components {
+			if strings.EqualFold(component.Name, componentName) {
+				newIssue.Components[i] = component.Name
+			}
+		}
+	}
+
+	return newIssue, draftPath, nil
+}
+
+// getIssueDraftTemplate returns issue draft with the summary, description, type and components of the issue template
+// and help listing issue types and components of the project
+func getIssueDraftTemplate(summary string, issueTemplate config.IssueTemplate, project jira.Project, projectErr error) string {
+	var template strings.Builder
+	template.WriteString(summary + "\n\n")
+	if issueTemplate.Description != "" {
+		template.WriteString(issueTemplate.Description + "\n\n")
+	}
+	if issueTemplate.IssueType != "" {
+		template.WriteString("Type: " + issueTemplate.IssueType + "\n")
+	}
+	if len(issueTemplate.Components) > 0 {
+		template.WriteString("Components: " + strings.Join(issueTemplate.Components, ", ") + "\n")
+	}
+	if issueTemplate.IssueType != "" || len(issueTemplate.Components) > 0 {
+		template.WriteString("\n")
+	}
+	template.WriteString(issueScissorsLine + "\n" + issueDraftHelp)
+
+	if projectErr != nil {
+		template.WriteString(fmt.Sprintf(
+			"# Issue types and components of project %s are not available: %s\n",
+			config.Options.Jira.ProjectCode, projectErr.Error(),
+		))
+		return template.String()
+	}
+
+	var issueTypes []string
+	for _, issueType := range project.IssueTypes {
+		if !issueType.Subtask {
+			issueTypes = append(issueTypes, issueType.Name)
+		}
+	}
+	template.WriteString(fmt.Sprintf("# Issue types: %s (default %s)\n", strings.Join(issueTypes, ", "), jira.DefaultIssueType))
+
+	if len(project.Components) == 0 {
+		template.WriteString(fmt.Sprintf("# Project %s has no components\n", project.Key))
+		return template.String()
+	}
+	var components []string
+	for _, component := range project.Components {
+		components = append(components, component.Name)
+	}
+	template.WriteString(fmt.Sprintf("# Components: %s\n", strings.Join(components, ", ")))
+
+	return template.String()
+}
+
+// parseIssueDraft returns issue with summary from the first line of the draft and description converted from Markdown.
+// Type and components are taken from the last paragraph of the description when it consists of 'Type:' and 'Components:' lines only.
+func parseIssueDraft(draft string) jira.NewIssue {
+	if index := strings.Index(draft, issueScissorsLine); index >= 0 {
+		draft = draft[:index]
+	}
+	draft = strings.TrimSpace(strings.ReplaceAll(draft, "\r\n", "\n"))
+
+	lines := strings.SplitN(draft, "\n", 2)
+	newIssue := jira.NewIssue{Summary: strings.TrimSpace(lines[0])}
+	if len(lines) == 1 {
+		return newIssue
+	}
+
+	description := strings.TrimSpace(lines[1])
+	trailersStart := strings.LastIndex(description, "\n\n") + 1
+	trailersIssue := jira.NewIssue{}
+	isTrailers := true
+	for _, line := range strings.Split(strings.TrimSpace(description[trailersStart:]), "\n") {
+		matches := issueDraftTrailerRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			isTrailers = false
+			break
+		}
+		if strings.EqualFold(matches[1], "type") {
+			trailersIssue.IssueType = matches[2]
+			continue
+		}
+		for _, component := range strings.Split(matches[2], ",") {
+			if component = strings.TrimSpace(component); component != "" {
+				trailersIssue.Components = append(trailersIssue.Components, component)
+			}
+		}
+	}
+	if isTrailers {
+		newIssue.IssueType = trailersIssue.IssueType
+		newIssue.Components = trailersIssue.Components
+		description = strings.TrimSpace(description[:trailersStart])
+	}
+
+	if description != "" {
+		document := jira.MarkdownToADF(description)
+		newIssue.Description = &document
+	}
+
+	return newIssue
+}
+
+// runEditor opens the file in the editor from VISUAL or EDITOR environment variables and waits for it to be closed.
+// Like in git, the editor command is run by shell, so it may contain arguments, and vi is used by default.
+// The editor is not stopped by cancellation, Ctrl-C is left to the editor while it runs in the foreground.
+func runEditor(filePath string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	suspendInterrupts()
+	defer resumeInterrupts()
+
+	cmd := exec.Command("sh", "-c", editor+` "$@"`, editor, filePath)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("Failed to run editor '%s'. Error: '%s'", editor, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"got/pkg/config"
+	"got/pkg/jira"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseIssueDraft(t *testing.T) {
+	tests := []struct {
+		name            string
+		draft           string
+		wantSummary     string
+		wantType        string
+		wantComponents  []string
+		wantDescription string
+	}{
+		{"empty", "\n\n" + issueScissorsLine + "\n# Help\n", "", "", nil, ""},
+		{"summary only", "\n  Fix login  \n" + issueScissorsLine + "\nIgnored\n", "Fix login", "", nil, ""},
+		{
+			"description",
+			"Fix login\n\nUsers can not **log in**\n\n" + issueScissorsLine + "\n",
+			"Fix login", "", nil, "Users can not log in",
+		},
+		{
+			"trailers",
+			"Fix login\n\nSteps\n\ntype: Bug\nComponents: API, , Billing\n",
+			"Fix login", "Bug", []string{"API", "Billing"}, "Steps",
+		},
+		{"trailers without description", "Fix login\n\nType: Task", "Fix login", "Task", nil, ""},
+		{
+			"not trailers",
+			"Fix login\n\nType: Bug\nand something else",
+			"Fix login", "", nil, "Type: Bug and something else",
+		},
+	}
+
+	for _, tt := range tests {
+		newIssue := parseIssueDraft(tt.draft)
+		if newIssue.Summary != tt.wantSummary || newIssue.IssueType != tt.wantType ||
+			!reflect.DeepEqual(newIssue.Components, tt.wantComponents) {
+			t.Errorf(
+				"parseIssueDraft for %s returned %q, %q, %v, want %q, %q, %v", tt.name,
+				newIssue.Summary, newIssue.IssueType, newIssue.Components, tt.wantSummary, tt.wantType, tt.wantComponents,
+			)
+		}
+
+		description := ""
+		if newIssue.Description != nil {
+			description = getADFText(*newIssue.Description)
+		}
+		if description != tt.wantDescription {
+			t.Errorf("parseIssueDraft for %s returned description %q, want %q", tt.name, description, tt.wantDescription)
+		}
+	}
+}
+
+func TestGetIssueDraftTemplate(t *testing.T) {
+	project := jira.Project{
+		Key:        "PC",
+		IssueTypes: []jira.ProjectIssueType{{Name: "Story"}, {Name: "Bug"}, {Name: "Sub-task", Subtask: true}},
+		Components: []jira.IssueComponent{{Name: "API"}, {Name: "Billing"}},
+	}
+
+	template := getIssueDraftTemplate("Fix login", config.IssueTemplate{}, project, nil)
+	if !strings.HasPrefix(template, "Fix login\n\n"+issueScissorsLine+"\n") {
+		t.Errorf("getIssueDraftTemplate returned %q, want summary and scissors line first", template)
+	}
+	if !strings.Contains(template, "# Issue types: Story, Bug (default Story)\n# Components: API, Billing\n") {
+		t.Errorf("getIssueDraftTemplate returned %q, want issue types and components", template)
+	}
+	if newIssue := parseIssueDraft(template); newIssue.Summary != "Fix login" || newIssue.Description != nil {
+		t.Errorf("parseIssueDraft for unchanged template returned %+v, want summary only", newIssue)
+	}
+
+	template = getIssueDraftTemplate("", config.IssueTemplate{}, jira.Project{}, errors.New("offline"))
+	if !strings.HasSuffix(template, "are not available: offline\n") {
+		t.Errorf("getIssueDraftTemplate with project error returned %q", template)
+	}
+
+	issueTemplate := config.IssueTemplate{IssueType: "Bug", Components: []string{"API"}, Description: "## Steps"}
+	template = getIssueDraftTemplate("Fix login", issueTemplate, project, nil)
+	if !strings.HasPrefix(template, "Fix login\n\n## Steps\n\nType: Bug\nComponents: API\n\n"+issueScissorsLine) {
+		t.Errorf("getIssueDraftTemplate with issue template returned %q", template)
+	}
+	newIssue := parseIssueDraft(template)
+	if newIssue.IssueType != "Bug" || !reflect.DeepEqual(newIssue.Components, []string{"API"}) || newIssue.Description == nil {
+		t.Errorf("parseIssueDraft for issue template draft returned %+v, want bug with API component and description", newIssue)
+	}
+}
+
+func getADFText(node jira.ADFNode) string {
+	text := node.Text
+	for _, child := range node.Content {
+		if text != "" && child.Type == "paragraph" {
+			text += "\n"
+		}
+		text += getADFText(child)
+	}
+	return text
+}
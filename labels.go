@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/jira"
+	"strings"
+)
+
+// maxLabelSuggestions limits number of similar labels suggested for unknown label
+const maxLabelSuggestions = 5
+
+// manageLabels lists or updates labels of all Jira issues linked to the current branch
+func manageLabels(ctx context.Context) {
+	currentBranchName, err := repository.CurrentBranch(ctx)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	issueKeys := branchNamer.GetIssueKeysFromBranchName(currentBranchName)
+	if len(issueKeys) == 0 {
+		printErrorToConsole(fmt.Errorf(
+			"Branch name '%s' does not contain issue keys with prefix '%s'", currentBranchName, config.GetIssueKeyPrefix(),
+		))
+		return
+	}
+
+	action := config.Options.LabelsCommand.Action
+	if action == config.ListLabels {
+		printIssuesLabels(ctx, issueKeys)
+		return
+	}
+
+	labels := config.Options.Labels
+	if action != config.RemoveLabel && !config.Options.LabelsCommand.AllowNew {
+		labels, err = resolveProjectLabels(ctx, labels)
+		if err != nil {
+			printErrorToConsole(err)
+			return
+		}
+	}
+
+	var update jira.IssueUpdate
+	switch action {
+	case config.AddLabel:
+		update.Labels = labels
+	case config.RemoveLabel:
+		update.RemoveLabels = labels
+	case config.SetLabels:
+		update.SetLabels = labels
+	}
+
+	for _, issueKey := range issueKeys {
+		if err := jiraClient.UpdateIssue(ctx, issueKey, update); err != nil {
+			printErrorToConsole(err)
+		}
+	}
+	printIssuesLabels(ctx, issueKeys)
+}
+
+// printIssuesLabels prints labels of every issue on a separate line
+func printIssuesLabels(ctx context.Context, issueKeys []string) {
+	issues := fetchIssues(ctx, issueKeys)
+	for _, issueKey := range issueKeys {
+		issue, ok := issues[issueKey]
+		if !ok {
+			continue
+		}
+
+		labels := strings.Join(issue.Fields.Labels, ", ")
+		if labels == "" {
+			labels = "no labels"
+		}
+		printInfoToConsole(fmt.Sprintf("%s: %s", issueKey, labels))
+	}
+}
+
+// resolveProjectLabels replaces labels with labels already used in the project that match them
+// ignoring case or start with them. Labels matching no or several project labels are reported with suggestions.
+func resolveProjectLabels(ctx context.Context, labels []string) ([]string, error) {
+	projectLabels, err := jiraClient.GetProjectLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolvedLabels []string
+	var unknownLabels []string
+	for _, label := range labels {
+		resolvedLabel, suggestions := matchProjectLabel(label, projectLabels)
+		if resolvedLabel == "" {
+			message := fmt.Sprintf("'%s'", label)
+			if len(suggestions) > 0 {
+				message += fmt.Sprintf(" (did you mean '%s'?)", strings.Join(suggestions, "', '"))
+			}
+			unknownLabels = append(unknownLabels, message)
+			continue
+		}
+
+		if resolvedLabel != label {
+			printInfoToConsole(fmt.Sprintf("Using label '%s' for '%s'", resolvedLabel, label))
+		}
+		if !stringInSlice(resolvedLabel, resolvedLabels) {
+			resolvedLabels = append(resolvedLabels, resolvedLabel)
+		}
+	}
+
+	if len(unknownLabels) > 0 {
+		return nil, fmt.Errorf(
+			"Labels are not used in project %s: %s. Use -new to add new labels",
+			config.Options.Jira.ProjectCode, strings.Join(unknownLabels, ", "),
+		)
+	}
+
+	return resolvedLabels, nil
+}
+
+// matchProjectLabel returns project label equal to label ignoring case or the only project label starting with it.
+// Otherwise returns empty label and similar project labels.
+func matchProjectLabel(label string, projectLabels []string) (string, []string) {
+	lowerLabel := strings.ToLower(label)
+
+	var prefixMatches []string
+	for _, projectLabel := range projectLabels {
+		if projectLabel == label {
+			return projectLabel, nil
+		}
+		if strings.HasPrefix(strings.ToLower(projectLabel), lowerLabel) {
+			prefixMatches = append(prefixMatches, projectLabel)
+		}
+	}
+	for _, projectLabel := range prefixMatches {
+		if strings.EqualFold(projectLabel, label) {
+			return projectLabel, nil
+		}
+	}
+	if len(prefixMatches) == 1 {
+		return prefixMatches[0], nil
+	}
+	if len(prefixMatches) > 1 {
+		return "", limitLabels(prefixMatches)
+	}
+
+	var suggestions []string
+	for _, projectLabel := range projectLabels {
+		lowerProjectLabel := strings.ToLower(projectLabel)
+		if strings.Contains(lowerProjectLabel, lowerLabel) || getEditDistance(lowerProjectLabel, lowerLabel) <= 2 {
+			suggestions = append(suggestions, projectLabel)
+		}
+	}
+
+	return "", limitLabels(suggestions)
+}
+
+func limitLabels(labels []string) []string {
+	if len(labels) > maxLabelSuggestions {
+		return labels[:maxLabelSuggestions]
+	}
+	return labels
+}
+
+// getEditDistance returns Levenshtein distance between strings
+func getEditDistance(a string, b string) int {
+	aRunes, bRunes := []rune(a), []rune(b)
+	previousRow := make([]int, len(bRunes)+1)
+	for j := range previousRow {
+		previousRow[j] = j
+	}
+
+	for i := 1; i <= len(aRunes); i++ {
+		currentRow := make([]int, len(bRunes)+1)
+		currentRow[0] = i
+		for j := 1; j <= len(bRunes); j++ {
+			cost := 1
+			if aRunes[i-1] == bRunes[j-1] {
+				cost = 0
+			}
+			currentRow[j] = minInt(previousRow[j]+1, minInt(currentRow[j-1]+1, previousRow[j-1]+cost))
+		}
+		previousRow = currentRow
+	}
+
+	return previousRow[len(bRunes)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchProjectLabel(t *testing.T) {
+	projectLabels := []string{"Backend", "backend-api", "frontend", "repo-got", "repo-got-cli"}
+
+	tests := []struct {
+		label           string
+		wantLabel       string
+		wantSuggestions []string
+	}{
+		{"frontend", "frontend", nil},
+		{"backend", "Backend", nil},
+		{"front", "frontend", nil},
+		{"BACKEND-A", "backend-api", nil},
+		{"repo-g", "", []string{"repo-got", "repo-got-cli"}},
+		{"frontnd", "", []string{"frontend"}},
+		{"api", "", []string{"backend-api"}},
+		{"mobile", "", nil},
+	}
+
+	for _, tt := range tests {
+		label, suggestions := matchProjectLabel(tt.label, projectLabels)
+		if label != tt.wantLabel || !reflect.DeepEqual(suggestions, tt.wantSuggestions) {
+			t.Errorf(
+				"matchProjectLabel for '%s' returned %+v, %+v, want %+v, %+v",
+				tt.label, label, suggestions, tt.wantLabel, tt.wantSuggestions,
+			)
+		}
+	}
+}
@@ -1,119 +1,401 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"got/pkg/config"
 	"got/pkg/git"
 	"got/pkg/jira"
+	"os"
+	"os/signal"
 	"strings"
-	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// Jira client, git repository and branch namer configured with application options
+var (
+	jiraClient  *jira.Client
+	repository  git.Repository
+	branchNamer git.BranchNamer
 )
 
 func main() {
 	err := config.InitAndRequestAdditionalData()
 	if err != nil {
 		printErrorToConsole(err)
-		return
+		if isBlockingHookRun() {
+			os.Exit(1)
+		}
+		os.Exit(getExitCode())
+	}
+
+	ctx, stop := newInterruptibleContext()
+	ctx = git.WithCommandTimeout(ctx, config.Options.Timeout.Duration)
+
+	jiraClient = newJiraClient()
+	repository, err = git.OpenRepository(git.Backend(config.Options.Git.Backend))
+	if err != nil {
+		printErrorToConsole(err)
+		stop()
+		os.Exit(getExitCode())
+	}
+	if err := checkGitInstalled(); err != nil {
+		printErrorToConsole(err)
+		stop()
+		os.Exit(getExitCode())
+	}
+	branchNamer = git.BranchNamer{
+		Separator:   config.Options.IssueBranchSeparator,
+		ProjectCode: config.Options.Jira.ProjectCode,
 	}
 
 	switch config.Options.Operation {
 	case config.CheckoutBranch:
-		checkoutJiraBranch()
+		checkoutJiraBranch(ctx)
 	case config.CheckBranchForNewJiraIssue:
-		createJiraTicketAndCheckBranch()
+		createJiraTicketAndCheckBranch(ctx)
 	case config.ModifyBranch:
-		modifyBranch()
+		modifyBranch(ctx)
 	case config.PrintInfo:
-		printInfo()
+		printInfo(ctx)
 	case config.LinkJiraIssueToCurrentBranch:
-		linkJiraIssueToCurrentBranch()
+		linkJiraIssueToCurrentBranch(ctx)
 	case config.UnlinkJiraIssueFromCurrentBranch:
-		unlinkJiraIssueFromCurrentBranch()
+		unlinkJiraIssueFromCurrentBranch(ctx)
 	case config.AddLabels:
-		addLabels()
+		addLabels(ctx)
+	case config.InstallHooks:
+		installHooks(ctx)
+	case config.RunHook:
+		runHook(ctx)
+	case config.PrintTimesheet:
+		printTimesheet(ctx)
+	case config.ManageWorktrees:
+		manageWorktrees(ctx)
+	case config.PruneBranches:
+		pruneBranches(ctx)
+	case config.PrintBranches:
+		printBranches(ctx)
+	case config.ClearCache:
+		clearCache()
+	case config.ManageLabels:
+		manageLabels(ctx)
+	case config.CreatePullRequest:
+		createPullRequest(ctx)
 	}
+
+	interrupted := ctx.Err() != nil
+	stop()
+	if interrupted {
+		os.Exit(130)
+	}
+	os.Exit(getExitCode())
 }
 
-func checkoutJiraBranch() {
-	issue, err := jira.GetIssue(config.GetIssueKey())
+// errorsReported is set when an error is printed to console
+var errorsReported int32
+
+// getExitCode returns 1 when errors were reported. Hooks exit with 0, blocking hooks exit with 1 by themselves
+// when they fail, so that errors of optional steps like adding Jira links do not abort git operations.
+func getExitCode() int {
+	if atomic.LoadInt32(&errorsReported) == 0 || isHookRun() {
+		return 0
+	}
+	return 1
+}
+
+// newJiraClient returns Jira client configured with application options
+func newJiraClient() *jira.Client {
+	settings := jira.Settings{
+		APIEndpoint: config.Options.Jira.APIEndPoint,
+		Email:       config.Options.Jira.Email,
+		APIKey:      config.Options.Jira.APIKey,
+		ProjectCode: config.Options.Jira.ProjectCode,
+		Timeout:     config.Options.Timeout.Duration,
+		MaxRetries:  config.Options.Jira.MaxRetries,
+		CacheTTL:    config.Options.Cache.TTL.Duration,
+		Offline:     config.Options.Offline,
+	}
+	if cache, err := jira.DefaultCache(); err == nil {
+		settings.CacheDirectory = cache.Directory
+	}
+
+	return jira.NewClient(settings)
+}
+
+// interruptsSuspended is set while an interactive program like the editor runs in the foreground
+// and handles Ctrl-C itself
+var interruptsSuspended int32
+
+// suspendInterrupts makes Ctrl-C ignored by got until resumeInterrupts is called
+func suspendInterrupts() {
+	atomic.StoreInt32(&interruptsSuspended, 1)
+}
+
+func resumeInterrupts() {
+	atomic.StoreInt32(&interruptsSuspended, 0)
+}
+
+// checkGitInstalled fails before the requested operation starts when it runs git binary which is not installed
+func checkGitInstalled() error {
+	if git.IsGitInstalled() {
+		return nil
+	}
+
+	operation := getOperationRequiringGit()
+	if operation == "" {
+		return nil
+	}
+	return fmt.Errorf(
+		"%s requires git binary, which is not installed. Without it got only reads, switches, creates and renames branches",
+		operation,
+	)
+}
+
+// getOperationRequiringGit returns description of the requested operation when it runs git binary
+func getOperationRequiringGit() string {
+	switch config.Options.Operation {
+	case config.ManageWorktrees:
+		return "got worktrees"
+	case config.PruneBranches:
+		return "got prune"
+	case config.PrintBranches:
+		return "got branches"
+	case config.InstallHooks:
+		return "got hooks install"
+	case config.CreatePullRequest:
+		if !config.Options.PullRequest.NoPush {
+			return "Pushing the branch by got pr"
+		}
+	case config.CheckoutBranch, config.CheckBranchForNewJiraIssue:
+		if config.Options.Operation == config.CheckoutBranch && config.Options.Worktree.Enabled {
+			return "Checking out to a worktree"
+		}
+		if config.Options.DirtyWorkingTree == config.StashDirtyWorkingTree {
+			return "Stashing local changes"
+		}
+		if config.Options.FromBranch != "" || config.Options.BaseBranch.Default != "" ||
+			len(config.Options.BaseBranch.ByIssueType) > 0 {
+			return "Creating branches from base branches"
+		}
+	}
+	return ""
+}
+
+// newInterruptibleContext returns context cancelled on the first Ctrl-C or SIGTERM,
+// the second signal terminates the application immediately
+func newInterruptibleContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if sig == os.Interrupt && atomic.LoadInt32(&interruptsSuspended) == 1 {
+					continue
+				}
+				signal.Stop(signals)
+				fmt.Fprintln(os.Stderr, "Interrupted, cancelling running operations")
+				cancel()
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
+
+func checkoutJiraBranch(ctx context.Context) {
+	issue, err := jiraClient.GetIssue(ctx, config.GetIssueKey())
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	if config.Options.Worktree.Enabled {
+		checkoutJiraWorktree(ctx, issue)
+		return
+	}
+
+	branchName, err := git.FindBranchBySubstring(ctx, repository, config.GetIssueKey()+config.Options.IssueBranchSeparator)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	branchName, err := git.FindBranchBySubstring(config.GetIssueKey() + config.Options.IssueBranchSeparator)
+	stashedBranchName, err := prepareWorkingTreeForCheckout(ctx)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
 	if branchName != "" {
-		output, err := git.CheckoutBranch(branchName)
+		err = repository.Checkout(ctx, branchName)
 		if err != nil {
 			printErrorToConsole(err)
+			restoreAutoStash(ctx, stashedBranchName)
 			return
 		}
 
-		printInfoToConsole(string(output))
+		printInfoToConsole(fmt.Sprintf("Switched to branch '%s'", branchName))
+		restoreAutoStash(ctx, branchName)
 		return
 	}
 
-	var waitGroup sync.WaitGroup
-	waitGroup.Add(1)
-	go addRepoLabelToJiraIssue(&waitGroup, config.GetIssueKey())
+	tasks, tasksCtx := newTaskGroup(ctx)
+	tasks.Go(func() error {
+		return updateIssueForNewBranch(tasksCtx, issue.Key)
+	})
+
+	branchName, err = branchNamer.GenerateBranchName([]string{issue.Key}, issue.Fields.Summary)
+	if err != nil {
+		printErrorToConsole(err)
+		tasks.Stop()
+		restoreAutoStash(ctx, stashedBranchName)
+		return
+	}
 
-	branchName, err = git.GenerateBranchName([]string{issue.Key}, issue.Fields.Summary)
+	baseBranch, err := resolveBaseBranch(ctx, issue.Fields.IssueType.Name)
 	if err != nil {
 		printErrorToConsole(err)
+		tasks.Stop()
+		restoreAutoStash(ctx, stashedBranchName)
 		return
 	}
 
-	output, err := git.CheckoutNewBranch(branchName)
+	err = repository.CreateBranch(ctx, branchName, baseBranch)
 	if err != nil {
 		printErrorToConsole(err)
+		tasks.Stop()
+		restoreAutoStash(ctx, stashedBranchName)
 		return
 	}
 
-	waitGroup.Wait()
-	printInfoToConsole(string(output))
+	// only branches created successfully are linked
+	tasks.Go(func() error {
+		return linkIssuesToBranch(tasksCtx, "", branchName, []string{issue.Key})
+	})
+
+	if err := tasks.Wait(); err != nil {
+		printErrorToConsole(err)
+	}
+	printInfoToConsole(fmt.Sprintf("Switched to a new branch '%s'", branchName))
 	printJiraIssueData(issue)
 }
 
-func createJiraTicketAndCheckBranch() {
-	issueKey, err := jira.CreateIssue(config.Options.Summary)
+func createJiraTicketAndCheckBranch(ctx context.Context) {
+	stashedBranchName, err := prepareWorkingTreeForCheckout(ctx)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	var waitGroup sync.WaitGroup
-	waitGroup.Add(1)
-	go addRepoLabelToJiraIssue(&waitGroup, issueKey)
+	var issueTemplate config.IssueTemplate
+	if config.Options.IssueTemplate != "" {
+		issueTemplate, err = config.LoadIssueTemplate(config.Options.IssueTemplate)
+		if err != nil {
+			printErrorToConsole(err)
+			restoreAutoStash(ctx, stashedBranchName)
+			return
+		}
+	}
 
-	branchName, err := git.GenerateBranchName([]string{issueKey}, config.Options.Summary)
+	newIssue := newIssueFromTemplate(config.Options.Summary, issueTemplate)
+	draftPath := ""
+	// the composed issue is kept in the draft file until the issue is created
+	printDraftPath := func() {
+		if draftPath != "" {
+			printInfoToConsole(fmt.Sprintf("Jira issue draft is saved to %s", draftPath))
+		}
+	}
+	if config.Options.EditIssue {
+		newIssue, draftPath, err = composeIssue(ctx, config.Options.Summary, issueTemplate)
+		if err != nil {
+			printErrorToConsole(err)
+			printDraftPath()
+			restoreAutoStash(ctx, stashedBranchName)
+			return
+		}
+	}
+
+	issueType := newIssue.IssueType
+	if issueType == "" {
+		issueType = jira.DefaultIssueType
+	}
+	baseBranch, err := resolveBaseBranch(ctx, issueType)
 	if err != nil {
 		printErrorToConsole(err)
+		printDraftPath()
+		restoreAutoStash(ctx, stashedBranchName)
 		return
 	}
 
-	output, err := git.CheckoutNewBranch(branchName)
+	issueKey, err := jiraClient.CreateIssue(ctx, newIssue)
+	if err != nil {
+		printErrorToConsole(err)
+		printDraftPath()
+		restoreAutoStash(ctx, stashedBranchName)
+		return
+	}
+	if draftPath != "" {
+		os.Remove(draftPath)
+	}
+	printInfoToConsole(fmt.Sprintf("Created Jira issue %s", issueKey))
+
+	tasks, tasksCtx := newTaskGroup(ctx)
+	tasks.Go(func() error {
+		return updateIssueForNewBranch(tasksCtx, issueKey)
+	})
+
+	branchName, err := branchNamer.GenerateBranchName([]string{issueKey}, newIssue.Summary)
+	if issueTemplate.Branch != "" {
+		branchName, err = branchNamer.GenerateBranchNameFromPattern(
+			issueTemplate.Branch, []string{issueKey}, newIssue.Summary, issueType,
+		)
+	}
 	if err != nil {
 		printErrorToConsole(err)
+		tasks.Stop()
+		restoreAutoStash(ctx, stashedBranchName)
 		return
 	}
 
-	waitGroup.Wait()
-	printInfoToConsole(string(output))
+	err = repository.CreateBranch(ctx, branchName, baseBranch)
+	if err != nil {
+		printErrorToConsole(err)
+		tasks.Stop()
+		restoreAutoStash(ctx, stashedBranchName)
+		return
+	}
+
+	// only branches created successfully are linked
+	tasks.Go(func() error {
+		return linkIssuesToBranch(tasksCtx, "", branchName, []string{issueKey})
+	})
+
+	if err := tasks.Wait(); err != nil {
+		printErrorToConsole(err)
+	}
+	printInfoToConsole(fmt.Sprintf("Switched to a new branch '%s'", branchName))
 }
 
-func addLabels() {
-	currentBranchName, err := git.GetCurrentBranchName()
+func addLabels(ctx context.Context) {
+	currentBranchName, err := repository.CurrentBranch(ctx)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	issueKeys := git.GetIssueKeysFromBranchName(currentBranchName)
+	issueKeys := branchNamer.GetIssueKeysFromBranchName(currentBranchName)
 	if len(issueKeys) == 0 {
 		printErrorToConsole(fmt.Errorf(
 			"Branch name '%s' does not contain issue keys with prefix '%s'", currentBranchName, config.GetIssueKeyPrefix(),
@@ -122,7 +404,7 @@ func addLabels() {
 	}
 
 	issueKey := issueKeys[0]
-	newLabels, err := jira.AddIssueLabels(issueKey, config.Options.Labels)
+	newLabels, err := jiraClient.AddIssueLabels(ctx, issueKey, config.Options.Labels)
 	if err != nil {
 		printErrorToConsole(err)
 		return
@@ -131,14 +413,14 @@ func addLabels() {
 	printInfoToConsole(fmt.Sprintf("Jira issue labels updated to '%s'", strings.Join(newLabels, ", ")))
 }
 
-func modifyBranch() {
-	currentBranchName, err := git.GetCurrentBranchName()
+func modifyBranch(ctx context.Context) {
+	currentBranchName, err := repository.CurrentBranch(ctx)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	issueKeys := git.GetIssueKeysFromBranchName(currentBranchName)
+	issueKeys := branchNamer.GetIssueKeysFromBranchName(currentBranchName)
 	if len(issueKeys) == 0 {
 		printErrorToConsole(fmt.Errorf(
 			"Branch name '%s' does not contain issue keys with prefix '%s'", currentBranchName, config.GetIssueKeyPrefix(),
@@ -148,85 +430,85 @@ func modifyBranch() {
 
 	issueKey := issueKeys[0]
 
-	summary, err := jira.UpdateIssueSummary(issueKey, config.Options.Summary)
+	summary, err := jiraClient.UpdateIssueSummary(ctx, issueKey, config.Options.Summary)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 	printInfoToConsole(fmt.Sprintf("Jira issue summary updated to '%s'", summary))
 
-	newBranchName, err := git.GenerateBranchName(issueKeys, summary)
+	newBranchName, err := branchNamer.GenerateBranchName(issueKeys, summary)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	output, err := git.UpdateCurrentBranchName(newBranchName)
+	err = repository.RenameCurrentBranch(ctx, newBranchName)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	printInfoToConsole(string(output))
+	printInfoToConsole(fmt.Sprintf("Branch renamed to '%s'", newBranchName))
 }
 
-func linkJiraIssueToCurrentBranch() {
-	currentBranchName, err := git.GetCurrentBranchName()
+func linkJiraIssueToCurrentBranch(ctx context.Context) {
+	currentBranchName, err := repository.CurrentBranch(ctx)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	issueKeys := git.GetIssueKeysFromBranchName(currentBranchName)
+	issueKeys := branchNamer.GetIssueKeysFromBranchName(currentBranchName)
 	issueKey := config.GetIssueKey()
 	if stringInSlice(issueKey, issueKeys) {
 		printInfoToConsole(fmt.Sprintf("Jira issue %s already linked to the current branch", issueKey))
 		return
 	}
 
-	updatedBranchName, err := git.PrependIssueKeysToBranchName([]string{issueKey}, currentBranchName)
+	updatedBranchName, err := branchNamer.PrependIssueKeysToBranchName([]string{issueKey}, currentBranchName)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	output, err := git.UpdateCurrentBranchName(updatedBranchName)
+	err = repository.RenameCurrentBranch(ctx, updatedBranchName)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	printInfoToConsole(string(output))
+	printInfoToConsole(fmt.Sprintf("Branch renamed to '%s'", updatedBranchName))
 }
 
-func unlinkJiraIssueFromCurrentBranch() {
-	currentBranchName, err := git.GetCurrentBranchName()
+func unlinkJiraIssueFromCurrentBranch(ctx context.Context) {
+	currentBranchName, err := repository.CurrentBranch(ctx)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
 	issueKey := config.GetIssueKey()
-	updatedBranchName := git.RemoveIssueKeysFromBranchName([]string{issueKey}, currentBranchName)
+	updatedBranchName := branchNamer.RemoveIssueKeysFromBranchName([]string{issueKey}, currentBranchName)
 
-	output, err := git.UpdateCurrentBranchName(updatedBranchName)
+	err = repository.RenameCurrentBranch(ctx, updatedBranchName)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
 	printInfoToConsole(fmt.Sprintf("Jira issue '%s' was unlinked from the current branch", issueKey))
-	printInfoToConsole(string(output))
+	printInfoToConsole(fmt.Sprintf("Branch renamed to '%s'", updatedBranchName))
 }
 
-func printInfo() {
-	currentBranchName, err := git.GetCurrentBranchName()
+func printInfo(ctx context.Context) {
+	currentBranchName, err := repository.CurrentBranch(ctx)
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	issueKeys := git.GetIssueKeysFromBranchName(currentBranchName)
+	issueKeys := branchNamer.GetIssueKeysFromBranchName(currentBranchName)
 	if len(issueKeys) == 0 {
 		printErrorToConsole(fmt.Errorf(
 			"Branch name '%s' does not contain issue keys with prefix '%s'", currentBranchName, config.GetIssueKeyPrefix(),
@@ -234,33 +516,58 @@ func printInfo() {
 		return
 	}
 
-	for i := 0; i < len(issueKeys); i++ {
-		issue, err := jira.GetIssue(issueKeys[i])
-		if err != nil {
-			printErrorToConsole(err)
+	issues := fetchIssues(ctx, issueKeys)
+	for _, issueKey := range issueKeys {
+		issue, ok := issues[issueKey]
+		if !ok {
 			continue
 		}
 		printJiraIssueData(issue)
 	}
 }
 
-func addRepoLabelToJiraIssue(waitGroup *sync.WaitGroup, issueKey string) {
-	defer waitGroup.Done()
+// fetchIssues returns Jira issues by keys, issues that failed to load are reported and skipped
+func fetchIssues(ctx context.Context, issueKeys []string) map[string]jira.Issue {
+	issues := map[string]jira.Issue{}
+	if len(issueKeys) == 0 {
+		return issues
+	}
+
+	foundIssues, err := jiraClient.GetIssues(ctx, issueKeys)
+	if err != nil {
+		printErrorToConsole(err)
+	}
+	for _, issue := range foundIssues {
+		issues[issue.Key] = issue
+	}
+
+	for _, issueKey := range issueKeys {
+		if _, ok := issues[issueKey]; !ok && err == nil {
+			if config.Options.Offline {
+				printErrorToConsole(fmt.Errorf("Jira ticket with key %s is not cached, run without -offline to fetch it", issueKey))
+				continue
+			}
+			printErrorToConsole(fmt.Errorf("Jira ticket with key %s not found", issueKey))
+		}
+	}
+
+	return issues
+}
 
-	repoName, err := git.GetRepositoryName()
+func clearCache() {
+	cache, err := jira.DefaultCache()
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	repoNameLabel := fmt.Sprintf("repo-%s", repoName)
-	newLabels, err := jira.AddIssueLabels(issueKey, []string{repoNameLabel})
+	err = cache.Clear()
 	if err != nil {
 		printErrorToConsole(err)
 		return
 	}
 
-	printInfoToConsole(fmt.Sprintf("Added labels to the Jira issue: '%s'", strings.Join(newLabels, ", ")))
+	printInfoToConsole(fmt.Sprintf("Removed cached Jira issues from '%s'", cache.Directory))
 }
 
 func printInfoToConsole(data string) {
@@ -270,9 +577,47 @@ func printInfoToConsole(data string) {
 }
 
 func printErrorToConsole(err error) {
+	atomic.StoreInt32(&errorsReported, 1)
 	fmt.Println(fmt.Sprintf("[ERROR] %s", err.Error()))
 }
 
+// askForConfirmation asks yes/no question, questions are answered with yes by -yes flag
+// and fail without waiting for an answer when stdin is not a terminal
+func askForConfirmation(ctx context.Context, question string) (bool, error) {
+	if config.Options.AssumeYes {
+		fmt.Printf("%s [y/N]: yes\n", question)
+		return true, nil
+	}
+	if !config.IsInteractive() {
+		return false, fmt.Errorf("Cannot confirm '%s' because stdin is not a terminal, run with -yes to confirm", question)
+	}
+
+	fmt.Printf("%s [y/N]: ", question)
+
+	answers := make(chan string, 1)
+	readErrors := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReader(os.Stdin)
+		answer, err := reader.ReadString('\n')
+		if err != nil {
+			readErrors <- err
+			return
+		}
+		answers <- answer
+	}()
+
+	select {
+	case <-ctx.Done():
+		fmt.Println()
+		return false, ctx.Err()
+	case err := <-readErrors:
+		return false, fmt.Errorf("Failed to read confirmation: %s", err.Error())
+	case answer := <-answers:
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes", nil
+	}
+}
+
 func printJiraIssueData(issue jira.Issue) {
 	fmt.Println(fmt.Sprintf("---------%s---------", issue.Key))
 	fmt.Println(fmt.Sprintf("Summary: %s", issue.Fields.Summary))
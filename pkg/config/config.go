@@ -5,8 +5,14 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"got/pkg/git"
+	"io"
+	"io/ioutil"
 	"os"
+	"regexp"
 	"strings"
+	"time"
+	"unicode"
 )
 
 // OperationType is a type for enum values of requested by user operation
@@ -21,31 +27,195 @@ const (
 	LinkJiraIssueToCurrentBranch     OperationType = "LinkJiraIssueToCurrentBranch"
 	UnlinkJiraIssueFromCurrentBranch OperationType = "UnlinkJiraIssueFromCurrentBranch"
 	AddLabels                        OperationType = "AddLabels"
+	InstallHooks                     OperationType = "InstallHooks"
+	RunHook                          OperationType = "RunHook"
+	PrintTimesheet                   OperationType = "PrintTimesheet"
+	ManageWorktrees                  OperationType = "ManageWorktrees"
+	PruneBranches                    OperationType = "PruneBranches"
+	PrintBranches                    OperationType = "PrintBranches"
+	ClearCache                       OperationType = "ClearCache"
+	ManageLabels                     OperationType = "ManageLabels"
+	CreatePullRequest                OperationType = "CreatePullRequest"
+)
+
+// LabelsAction is a type for enum values of got labels subcommands
+type LabelsAction string
+
+// ListLabels is a holder of labels action name
+const (
+	ListLabels  LabelsAction = "list"
+	AddLabel    LabelsAction = "add"
+	RemoveLabel LabelsAction = "remove"
+	SetLabels   LabelsAction = "set"
 )
 
 // OptionsType is a type for stored app configuration
 type OptionsType struct {
-	IssueCode            int
-	Summary              string
-	Labels               []string
-	Operation            OperationType
-	IssueBranchSeparator string
-	Jira                 struct {
-		ProjectCode string
-		APIEndPoint string
-		Email       string
-		APIKey      string
+	IssueCode            int           `json:"-"`
+	Summary              string        `json:"-"`
+	EditIssue            bool          `json:"-"`
+	IssueTemplate        string        `json:"-"`
+	Labels               []string      `json:"-"`
+	Operation            OperationType `json:"-"`
+	IssueBranchSeparator string        `json:"issueBranchSeparator"`
+	Timeout              Duration      `json:"timeout"`
+	Offline              bool          `json:"-"`
+	AssumeYes            bool          `json:"-"`
+	Hook                 struct {
+		Name string
+		Args []string
+	} `json:"-"`
+	Timesheet struct {
+		Days   int
+		Submit bool
+	} `json:"-"`
+	FromBranch     string `json:"-"`
+	PruneWorktrees bool   `json:"-"`
+	Prune          struct {
+		Remote     bool
+		DryRun     bool
+		BaseBranch string
+	} `json:"-"`
+	LabelsCommand struct {
+		Action   LabelsAction
+		AllowNew bool
+	} `json:"-"`
+	PullRequest struct {
+		BaseBranch string
+		NoPush     bool
+	} `json:"-"`
+	DirtyWorkingTree DirtyWorkingTreeStrategy `json:"dirtyWorkingTree"`
+	Worktree         struct {
+		Enabled   bool   `json:"enabled"`
+		Directory string `json:"directory"`
+	} `json:"worktree"`
+	Branches struct {
+		BaseBranch string `json:"-"`
+		StaleDays  int    `json:"staleDays"`
+	} `json:"branches"`
+	BaseBranch struct {
+		Default     string            `json:"default"`
+		ByIssueType map[string]string `json:"byIssueType"`
+	} `json:"baseBranch"`
+	CommitMessage struct {
+		IssueKeysPosition git.IssueKeysPosition `json:"issueKeysPosition"`
+	} `json:"commitMessage"`
+	PrePush struct {
+		IssueKeyPatterns []string `json:"issueKeyPatterns"`
+		AllowedBranches  []string `json:"allowedBranches"`
+		CheckIssueStatus bool     `json:"checkIssueStatus"`
+	} `json:"prePush"`
+	Git struct {
+		Backend string `json:"backend"`
+		Remote  string `json:"remote"`
+	} `json:"git"`
+	BranchCreation BranchCreationOptions `json:"branchCreation"`
+	Cache          CacheOptions          `json:"cache"`
+	Jira           JiraOptions           `json:"jira"`
+	Forge          ForgeOptions          `json:"forge"`
+}
+
+// BranchCreationOptions is a type for changes applied to Jira issues when branches are created for them.
+// Labels and string field values are templates with {host}, {owner}, {repo} and {path} placeholders.
+type BranchCreationOptions struct {
+	RepoLabel        string                 `json:"repoLabel"`
+	DisableRepoLabel bool                   `json:"disableRepoLabel"`
+	Labels           []string               `json:"labels"`
+	Components       []string               `json:"components"`
+	Fields           map[string]interface{} `json:"fields"`
+	Rules            []BranchCreationRule   `json:"rules"`
+}
+
+// BranchCreationRule is a type for labels, components and fields applied to issues of matching repositories
+type BranchCreationRule struct {
+	// RepoPath is a glob pattern of repository owner and name, e.g. 'payments/*'
+	RepoPath   string                 `json:"repoPath"`
+	Labels     []string               `json:"labels"`
+	Components []string               `json:"components"`
+	Fields     map[string]interface{} `json:"fields"`
+}
+
+// CacheOptions is a type for settings of the local Jira issues cache
+type CacheOptions struct {
+	TTL Duration `json:"ttl"`
+}
+
+// JiraOptions is a type for Jira connection settings
+type JiraOptions struct {
+	ProjectCode string `json:"projectCode"`
+	APIEndPoint string `json:"apiEndpoint"`
+	Email       string `json:"email"`
+	APIKey      string `json:"apiKey"`
+	MaxRetries  int    `json:"maxRetries"`
+	// DisableRemoteLinks disables links of Jira issues to their branches and pull requests
+	DisableRemoteLinks bool `json:"disableRemoteLinks"`
+}
+
+// ForgeOptions is a type for settings of git hosting services pull requests are created on
+type ForgeOptions struct {
+	// Type is github, gitlab or bitbucket, it is detected from the remote host when empty
+	Type string `json:"type"`
+	// Hosts are settings and credentials of git hosting services by host names of remote URLs
+	Hosts map[string]ForgeHostOptions `json:"hosts"`
+}
+
+// ForgeHostOptions is a type for settings of a git hosting service host, its token is sent only to its API
+type ForgeHostOptions struct {
+	// Type is github, gitlab or bitbucket, it overrides ForgeOptions.Type for the host
+	Type string `json:"type"`
+	// APIURL is REST API base URL, it is required for hosts other than github.com, gitlab.com and bitbucket.org
+	APIURL string `json:"apiUrl"`
+	// WebURL overrides base URL of branch pages linked to Jira issues, 'https://' and the host by default
+	WebURL   string `json:"webUrl"`
+	Token    string `json:"token"`
+	Username string `json:"username"`
+}
+
+// GetForgeHostOptions returns settings of the git hosting service host ignoring case of the host name
+func GetForgeHostOptions(host string) ForgeHostOptions {
+	for hostName, hostOptions := range Options.Forge.Hosts {
+		if strings.EqualFold(hostName, host) {
+			return hostOptions
+		}
 	}
+	return ForgeHostOptions{}
 }
 
+// DirtyWorkingTreeStrategy is a type for enum values of local changes handling when switching branches
+type DirtyWorkingTreeStrategy string
+
+// AbortOnDirtyWorkingTree is a holder of dirty working tree strategy name
+const (
+	AbortOnDirtyWorkingTree DirtyWorkingTreeStrategy = "abort"
+	StashDirtyWorkingTree   DirtyWorkingTreeStrategy = "stash"
+	CarryDirtyWorkingTree   DirtyWorkingTreeStrategy = "carry"
+)
+
+// maxPipedInputSize limits size of summary and labels read from piped stdin
+const maxPipedInputSize = 64 * 1024
+
 // Options variable stores app configuration settings
 var Options OptionsType = OptionsType{
 	IssueBranchSeparator: "/",
+	Timeout:              Duration{30 * time.Second},
+	DirtyWorkingTree:     CarryDirtyWorkingTree,
+	BranchCreation:       BranchCreationOptions{RepoLabel: "repo-{repo}"},
+	Cache:                CacheOptions{TTL: Duration{5 * time.Minute}},
+	Jira:                 JiraOptions{MaxRetries: 3},
 }
 
 // InitAndRequestAdditionalData function initializes global configuration of the application
 func InitAndRequestAdditionalData() error {
-	err := readConfigVariables()
+	err := readConfigFiles()
+	if err != nil {
+		return err
+	}
+
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		return parseCommand(os.Args[1], os.Args[2:])
+	}
+
+	err = readConfigVariables()
 	if err != nil {
 		return err
 	}
@@ -54,10 +224,31 @@ func InitAndRequestAdditionalData() error {
 	modifyBranch := flag.Bool("m", false, "Update branch name with Jira issue summary")
 	addLabels := flag.Bool("al", false, "Add labels to jira issue")
 	createIssue := flag.Bool("cj", false, "Create a new Jira issue and switch to the new branch")
+	editIssue := flag.Bool("e", false, "Compose summary and description of the issue created with -cj in $EDITOR")
+	issueTemplate := flag.String("template", "", "Name of .got/templates/NAME.md template of the issue created with -cj")
 	printIssuesInfo := flag.Bool("info", false, "Print current branch Jira issues information")
 	issueCodeForLinking := flag.Int("lj", 0, "Links Jira Issue to current branch")
 	issueCodeForUnlinking := flag.Int("uj", 0, "Unlinks Jira Issue from the current branch")
+	useWorktree := flag.Bool("worktree", Options.Worktree.Enabled, "Use git worktree for the branch checked out with -b")
+	dirtyWorkingTree := flag.String(
+		"dirty", string(Options.DirtyWorkingTree), "Local changes handling when switching branches: carry, abort or stash",
+	)
+	fromBranch := flag.String("from", "", "Base branch for new issue branches created with -b and -cj")
+	summary := flag.String("summary", "", "Jira issue summary for -cj and -m, can be passed as arguments or piped to stdin as well")
+	labels := flag.String("labels", "", "Comma separated labels for -al, can be passed as arguments or piped to stdin as well")
+	applyCommonFlags := registerCommonFlags(flag.CommandLine)
 	flag.Parse()
+	applyCommonFlags()
+
+	Options.FromBranch = *fromBranch
+	Options.IssueTemplate = *issueTemplate
+	Options.Worktree.Enabled = *useWorktree
+	Options.DirtyWorkingTree = DirtyWorkingTreeStrategy(*dirtyWorkingTree)
+	switch Options.DirtyWorkingTree {
+	case AbortOnDirtyWorkingTree, StashDirtyWorkingTree, CarryDirtyWorkingTree:
+	default:
+		return fmt.Errorf("Invalid local changes handling strategy '%s', use abort, stash or carry", *dirtyWorkingTree)
+	}
 
 	if *ticketID < 0 {
 		return errors.New("Jira ticket number ket should be more than 0")
@@ -81,21 +272,31 @@ func InitAndRequestAdditionalData() error {
 		return nil
 	}
 
+	if *createIssue && *editIssue {
+		Options.Operation = CheckBranchForNewJiraIssue
+		Options.EditIssue = true
+		Options.Summary = strings.TrimSpace(*summary)
+		if Options.Summary == "" {
+			Options.Summary = strings.TrimSpace(strings.Join(flag.Args(), " "))
+		}
+		return nil
+	}
+
 	if *createIssue {
 		Options.Operation = CheckBranchForNewJiraIssue
-		err := readJiraIssueSummary()
+		err := readJiraIssueSummary(*summary, flag.Args())
 		return err
 	}
 
 	if *modifyBranch {
 		Options.Operation = ModifyBranch
-		err := readJiraIssueSummary()
+		err := readJiraIssueSummary(*summary, flag.Args())
 		return err
 	}
 
 	if *addLabels {
 		Options.Operation = AddLabels
-		err := readLabels()
+		err := readLabels(*labels, flag.Args())
 		return err
 	}
 
@@ -107,45 +308,221 @@ func InitAndRequestAdditionalData() error {
 	return errors.New("Invalid flags supplied. Cannot determine target operation, use --help")
 }
 
+func parseCommand(command string, args []string) error {
+	switch command {
+	case "hooks":
+		if len(args) == 0 || args[0] != "install" {
+			return errors.New("Unknown hooks command, use 'got hooks install'")
+		}
+		Options.Operation = InstallHooks
+		return nil
+	case "cache":
+		if len(args) == 0 || args[0] != "clear" {
+			return errors.New("Unknown cache command, use 'got cache clear'")
+		}
+		Options.Operation = ClearCache
+		return nil
+	case "labels":
+		if len(args) == 0 {
+			return errors.New("Labels command is not specified, use 'got labels list|add|remove|set'")
+		}
+
+		action := LabelsAction(args[0])
+		switch action {
+		case ListLabels, AddLabel, RemoveLabel, SetLabels:
+		default:
+			return fmt.Errorf("Unknown labels command '%s', use 'got labels list|add|remove|set'", args[0])
+		}
+
+		flagSet := flag.NewFlagSet("labels "+args[0], flag.ExitOnError)
+		allowNew := flagSet.Bool("new", false, "Allow labels that are not used in the project yet")
+		applyCommonFlags := registerCommonFlags(flagSet)
+		flagSet.Parse(args[1:])
+		applyCommonFlags()
+
+		if action != ListLabels && flagSet.NArg() == 0 {
+			return fmt.Errorf("Labels are not specified, use 'got labels %s LABEL...'", action)
+		}
+
+		Options.Operation = ManageLabels
+		Options.LabelsCommand.Action = action
+		Options.LabelsCommand.AllowNew = *allowNew
+		Options.Labels = flagSet.Args()
+		return readConfigVariables()
+	case "hook":
+		if len(args) == 0 {
+			return errors.New("Hook name is not specified")
+		}
+		Options.Operation = RunHook
+		Options.Hook.Name = args[0]
+		Options.Hook.Args = args[1:]
+		switch Options.Hook.Name {
+		case "post-checkout":
+			return nil
+		case "prepare-commit-msg":
+			// commits are not blocked by missing Jira settings, issue keys are found with the project code only
+			Options.Jira.ProjectCode, _ = getJiraProjectCode()
+			return nil
+		}
+		return readConfigVariables()
+	case "timesheet":
+		flagSet := flag.NewFlagSet("timesheet", flag.ExitOnError)
+		days := flagSet.Int("days", 7, "Number of days to summarise including today")
+		submit := flagSet.Bool("submit", false, "Submit not yet submitted time as Jira worklogs")
+		applyCommonFlags := registerCommonFlags(flagSet)
+		flagSet.Parse(args)
+		applyCommonFlags()
+
+		if *days <= 0 {
+			return errors.New("Number of days should be more than 0")
+		}
+
+		Options.Operation = PrintTimesheet
+		Options.Timesheet.Days = *days
+		Options.Timesheet.Submit = *submit
+		return readConfigVariables()
+	case "worktrees":
+		flagSet := flag.NewFlagSet("worktrees", flag.ExitOnError)
+		prune := flagSet.Bool("prune", false, "Remove stale worktrees and worktrees of resolved Jira issues")
+		applyCommonFlags := registerCommonFlags(flagSet)
+		flagSet.Parse(args)
+		applyCommonFlags()
+
+		Options.Operation = ManageWorktrees
+		Options.PruneWorktrees = *prune
+		return readConfigVariables()
+	case "prune":
+		flagSet := flag.NewFlagSet("prune", flag.ExitOnError)
+		remote := flagSet.Bool("remote", false, "Delete remote branches as well")
+		dryRun := flagSet.Bool("dry-run", false, "Only print branches that would be deleted")
+		baseBranch := flagSet.String("base", "", "Branch that deleted branches should be merged into")
+		applyCommonFlags := registerCommonFlags(flagSet)
+		flagSet.Parse(args)
+		applyCommonFlags()
+
+		Options.Operation = PruneBranches
+		Options.Prune.Remote = *remote
+		Options.Prune.DryRun = *dryRun
+		Options.Prune.BaseBranch = *baseBranch
+		return readConfigVariables()
+	case "pr":
+		flagSet := flag.NewFlagSet("pr", flag.ExitOnError)
+		baseBranch := flagSet.String("base", "", "Branch the pull request is merged into, the default branch of the repository by default")
+		noPush := flagSet.Bool("no-push", false, "Do not push the current branch before creating the pull request")
+		applyCommonFlags := registerCommonFlags(flagSet)
+		flagSet.Parse(args)
+		applyCommonFlags()
+
+		Options.Operation = CreatePullRequest
+		Options.PullRequest.BaseBranch = *baseBranch
+		Options.PullRequest.NoPush = *noPush
+		return readConfigVariables()
+	case "branches":
+		if Options.Branches.StaleDays <= 0 {
+			Options.Branches.StaleDays = 14
+		}
+
+		flagSet := flag.NewFlagSet("branches", flag.ExitOnError)
+		baseBranch := flagSet.String("base", "", "Branch to compare issue branches with")
+		staleDays := flagSet.Int(
+			"stale-days", Options.Branches.StaleDays, "Days without commits after which branches of issues in progress are reported",
+		)
+		applyCommonFlags := registerCommonFlags(flagSet)
+		flagSet.Parse(args)
+		applyCommonFlags()
+
+		Options.Operation = PrintBranches
+		Options.Branches.BaseBranch = *baseBranch
+		Options.Branches.StaleDays = *staleDays
+		return readConfigVariables()
+	default:
+		return fmt.Errorf("Unknown command '%s', use --help", command)
+	}
+}
+
+// registerCommonFlags adds flags supported by all operations to the flag set
+// and returns function that applies parsed values to the options
+func registerCommonFlags(flagSet *flag.FlagSet) func() {
+	timeout := flagSet.Duration(
+		"timeout", Options.Timeout.Duration, "Timeout of a single Jira request or git command, 0 disables the timeout",
+	)
+
+	offline := flagSet.Bool("offline", false, "Read Jira issues from the local cache without sending requests to Jira")
+	assumeYes := flagSet.Bool("yes", false, "Answer yes to confirmations, required to confirm when stdin is not a terminal")
+
+	return func() {
+		Options.Timeout.Duration = *timeout
+		Options.Offline = *offline
+		Options.AssumeYes = *assumeYes
+	}
+}
+
 // GetIssueKey returns a key of the Jira issue that contains project code and issue code
 func GetIssueKey() string {
 	return fmt.Sprintf("%s-%d", Options.Jira.ProjectCode, Options.IssueCode)
 }
 
+// GetPrePushIssueKeyPatterns returns regular expressions for issue keys required in pushed branch names.
+// The default pattern matches issue keys of the project separated with the issue branch separator.
+func GetPrePushIssueKeyPatterns() []string {
+	if len(Options.PrePush.IssueKeyPatterns) > 0 {
+		return Options.PrePush.IssueKeyPatterns
+	}
+
+	separator := regexp.QuoteMeta(Options.IssueBranchSeparator)
+	return []string{fmt.Sprintf("(?:^|%s)(%s[0-9]+)(?:%s|$)", separator, regexp.QuoteMeta(GetIssueKeyPrefix()), separator)}
+}
+
+// GetPrePushAllowedBranches returns patterns of branch names that can be pushed without issue keys
+func GetPrePushAllowedBranches() []string {
+	if len(Options.PrePush.AllowedBranches) > 0 {
+		return Options.PrePush.AllowedBranches
+	}
+	return []string{"main", "master", "develop"}
+}
+
 // GetIssueKeyPrefix returns Jira issue key prefix
 func GetIssueKeyPrefix() string {
 	return fmt.Sprintf("%s-", Options.Jira.ProjectCode)
 }
 
-func readLabels() error {
-	fmt.Print("Enter Jira issue labels to add separated by space: ")
-
-	reader := bufio.NewReader(os.Stdin)
-	labels, err := reader.ReadString('\n')
-	if err != nil {
-		return fmt.Errorf("Failed to read string from buffer reader: %s", err.Error())
+// readLabels sets labels from the flag value, arguments, piped stdin or a prompt
+func readLabels(flagValue string, args []string) error {
+	labels := parseLabels(flagValue)
+	if len(labels) == 0 {
+		labels = parseLabels(strings.Join(args, " "))
+	}
+	if len(labels) == 0 {
+		input, err := readInput("Enter Jira issue labels to add separated by space: ", "labels with -labels flag or as arguments")
+		if err != nil {
+			return err
+		}
+		labels = parseLabels(input)
 	}
 
-	labels = strings.TrimSpace(labels)
 	if len(labels) == 0 {
 		return errors.New("Labels list cannot be an empty string")
 	}
 
-	Options.Labels = strings.Split(labels, " ")
+	Options.Labels = labels
 
 	return nil
 }
 
-func readJiraIssueSummary() error {
-	fmt.Print("Enter Jira issue summary: ")
-
-	reader := bufio.NewReader(os.Stdin)
-	summary, err := reader.ReadString('\n')
-	if err != nil {
-		return fmt.Errorf("Failed to read string from buffer reader: %s", err.Error())
+// readJiraIssueSummary sets summary from the flag value, arguments, the first line of piped stdin or a prompt
+func readJiraIssueSummary(flagValue string, args []string) error {
+	summary := strings.TrimSpace(flagValue)
+	if summary == "" {
+		summary = strings.TrimSpace(strings.Join(args, " "))
+	}
+	if summary == "" {
+		input, err := readInput("Enter Jira issue summary: ", "summary with -summary flag or as arguments")
+		if err != nil {
+			return err
+		}
+		summary = strings.TrimSpace(strings.SplitN(strings.TrimSpace(input), "\n", 2)[0])
 	}
 
-	summary = strings.TrimSpace(summary)
 	if len(summary) == 0 {
 		return errors.New("Summary cannot be an empty string")
 	}
@@ -155,6 +532,91 @@ func readJiraIssueSummary() error {
 	return nil
 }
 
+// stdin is read by prompts and for piped summary and labels
+var stdin = os.Stdin
+
+// pipedInputTimeout limits waiting for the first data of piped stdin, so that got does not block
+// on stdin inherited from a parent process that never writes to it
+var pipedInputTimeout = time.Second
+
+// IsInteractive checks if stdin is a terminal, so the user can answer prompts
+func IsInteractive() bool {
+	stat, err := stdin.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// readInput prompts for a line on terminal or reads the whole piped stdin.
+// Missing input is reported with a hint how to pass the value instead.
+func readInput(prompt string, hint string) (string, error) {
+	if !IsInteractive() {
+		input, err := readPipedInput()
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(input) == "" {
+			return "", fmt.Errorf("Stdin is not a terminal and has no input, pass %s", hint)
+		}
+		return input, nil
+	}
+
+	fmt.Print(prompt)
+
+	reader := bufio.NewReader(stdin)
+	input, err := reader.ReadString('\n')
+	if err == io.EOF && strings.TrimSpace(input) == "" {
+		fmt.Println()
+		return "", fmt.Errorf("No input was entered, pass %s", hint)
+	}
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("Failed to read string from buffer reader: %s", err.Error())
+	}
+
+	return input, nil
+}
+
+// readPipedInput reads stdin redirected from a file or a pipe. Pipes and sockets are read only when
+// data arrives within pipedInputTimeout, otherwise there is no input.
+func readPipedInput() (string, error) {
+	stat, err := stdin.Stat()
+	if err != nil {
+		return "", fmt.Errorf("Failed to read stdin: %s", err.Error())
+	}
+
+	reader := bufio.NewReader(io.LimitReader(stdin, maxPipedInputSize))
+	if !stat.Mode().IsRegular() {
+		ready := make(chan error, 1)
+		go func() {
+			_, err := reader.Peek(1)
+			ready <- err
+		}()
+
+		select {
+		case err := <-ready:
+			if err == io.EOF {
+				return "", nil
+			}
+			if err != nil {
+				return "", fmt.Errorf("Failed to read stdin: %s", err.Error())
+			}
+		case <-time.After(pipedInputTimeout):
+			return "", nil
+		}
+	}
+
+	input, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read stdin: %s", err.Error())
+	}
+	return string(input), nil
+}
+
+// parseLabels splits labels separated by spaces or commas
+func parseLabels(labels string) []string {
+	return strings.FieldsFunc(labels, func(char rune) bool {
+		return char == ',' || unicode.IsSpace(char)
+	})
+}
+
 func readConfigVariables() error {
 	var err error
 	Options.Jira.APIEndPoint, err = getJiraAPIEnpoint()
@@ -182,36 +644,48 @@ func readConfigVariables() error {
 
 func getJiraAPIKey() (string, error) {
 	apiKey := os.Getenv("JIRA_API_KEY")
+	if apiKey == "" {
+		apiKey = Options.Jira.APIKey
+	}
 
 	if apiKey == "" {
-		return "", errors.New("env variable JIRA_API_KEY not specified")
+		return "", errors.New("env variable JIRA_API_KEY or config option jira.apiKey not specified")
 	}
 	return apiKey, nil
 }
 
 func getJiraEmail() (string, error) {
 	email := os.Getenv("JIRA_EMAIL")
+	if email == "" {
+		email = Options.Jira.Email
+	}
 
 	if email == "" {
-		return "", errors.New("env variable JIRA_EMAIL not specified")
+		return "", errors.New("env variable JIRA_EMAIL or config option jira.email not specified")
 	}
 	return email, nil
 }
 
 func getJiraProjectCode() (string, error) {
 	projectCode := os.Getenv("JIRA_PROJECT_CODE")
+	if projectCode == "" {
+		projectCode = Options.Jira.ProjectCode
+	}
 
 	if projectCode == "" {
-		return "", errors.New("env variable JIRA_PROJECT_CODE not specified")
+		return "", errors.New("env variable JIRA_PROJECT_CODE or config option jira.projectCode not specified")
 	}
 	return projectCode, nil
 }
 
 func getJiraAPIEnpoint() (string, error) {
 	apiEndpoint := os.Getenv("JIRA_API_ENDPOINT")
+	if apiEndpoint == "" {
+		apiEndpoint = Options.Jira.APIEndPoint
+	}
 
 	if apiEndpoint == "" {
-		return "", errors.New("env variable JIRA_API_ENDPOINT not specified")
+		return "", errors.New("env variable JIRA_API_ENDPOINT or config option jira.apiEndpoint not specified")
 	}
 	return apiEndpoint, nil
 
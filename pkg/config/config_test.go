@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pipeStdin replaces stdin with a pipe, input is written to it and the pipe is closed unless keepOpen is set
+func pipeStdin(t *testing.T, input string, keepOpen bool) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.WriteString(input); err != nil {
+		t.Fatal(err)
+	}
+	if !keepOpen {
+		writer.Close()
+	}
+
+	savedStdin, savedOptions, savedTimeout := stdin, Options, pipedInputTimeout
+	stdin = reader
+	pipedInputTimeout = 100 * time.Millisecond
+	t.Cleanup(func() {
+		stdin, Options, pipedInputTimeout = savedStdin, savedOptions, savedTimeout
+		reader.Close()
+		writer.Close()
+	})
+}
+
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"backend frontend", []string{"backend", "frontend"}},
+		{"backend,frontend, api\n", []string{"backend", "frontend", "api"}},
+		{" , ", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := parseLabels(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseLabels(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestReadLabels(t *testing.T) {
+	tests := []struct {
+		name      string
+		flagValue string
+		args      []string
+		input     string
+		want      []string
+	}{
+		{"flag", "backend,api", []string{"ignored"}, "ignored", []string{"backend", "api"}},
+		{"arguments", "", []string{"backend", "api"}, "ignored", []string{"backend", "api"}},
+		{"stdin", "", nil, "backend\napi\n", []string{"backend", "api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeStdin(t, tt.input, false)
+
+			if err := readLabels(tt.flagValue, tt.args); err != nil {
+				t.Fatalf("readLabels returned error %+v", err)
+			}
+			if !reflect.DeepEqual(Options.Labels, tt.want) {
+				t.Errorf("readLabels set labels %+v, want %+v", Options.Labels, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadJiraIssueSummary(t *testing.T) {
+	tests := []struct {
+		name      string
+		flagValue string
+		args      []string
+		input     string
+		want      string
+	}{
+		{"flag", " Fix login ", []string{"ignored"}, "ignored", "Fix login"},
+		{"arguments", "", []string{"Fix", "login"}, "ignored", "Fix login"},
+		{"first line of stdin", "", nil, "\n Fix login \nDetails\n", "Fix login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeStdin(t, tt.input, false)
+
+			if err := readJiraIssueSummary(tt.flagValue, tt.args); err != nil {
+				t.Fatalf("readJiraIssueSummary returned error %+v", err)
+			}
+			if Options.Summary != tt.want {
+				t.Errorf("readJiraIssueSummary set summary %q, want %q", Options.Summary, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadJiraIssueSummary_WithoutInput(t *testing.T) {
+	for _, keepOpen := range []bool{false, true} {
+		pipeStdin(t, "", keepOpen)
+
+		err := readJiraIssueSummary("", nil)
+		if err == nil || !strings.Contains(err.Error(), "-summary") {
+			t.Errorf("readJiraIssueSummary with open pipe %t returned error %+v, want hint about -summary", keepOpen, err)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"got/pkg/git"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	configFileName            = "config.json"
+	repositoryConfigDirectory = ".got"
+)
+
+// Duration is a time.Duration stored in config files as a string like '30s' or '1m30s'
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON parses duration from a string
+func (duration *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return fmt.Errorf("duration should be a string like '30s': %s", err.Error())
+	}
+
+	duration.Duration, err = time.ParseDuration(value)
+	return err
+}
+
+// repositoryConfigKeys lists settings the repository config file can set, nil means all settings of the section.
+// Jira and git hosting service endpoints and credentials are read only from the user config file and environment variables,
+// so that a cloned repository cannot send them to another server.
+var repositoryConfigKeys = map[string][]string{
+	"issueBranchSeparator": nil,
+	"commitMessage":        nil,
+	"baseBranch":           nil,
+	"branches":             nil,
+	"prePush":              nil,
+	"branchCreation":       nil,
+	"jira":                 {"projectCode"},
+	"forge":                {"type"},
+}
+
+// readConfigFiles reads user configuration file and then repository configuration file,
+// so that repository settings override user settings
+func readConfigFiles() error {
+	userConfigDirectory, err := os.UserConfigDir()
+	if err == nil {
+		err = readConfigFile(filepath.Join(userConfigDirectory, "got", configFileName))
+		if err != nil {
+			return err
+		}
+	}
+
+	repositoryConfigPath, err := findRepositoryConfigFile()
+	if err != nil || repositoryConfigPath == "" {
+		return err
+	}
+
+	return readRepositoryConfigFile(repositoryConfigPath)
+}
+
+func readConfigFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to read config file '%s': %s", path, err.Error())
+	}
+
+	return parseConfigFile(path, data)
+}
+
+// readRepositoryConfigFile reads repository config file which can set only settings of repositoryConfigKeys
+func readRepositoryConfigFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read config file '%s': %s", path, err.Error())
+	}
+
+	key, err := findNotAllowedKey(data, repositoryConfigKeys)
+	if err != nil {
+		return fmt.Errorf("Failed to parse config file '%s': %s", path, err.Error())
+	}
+	if key != "" {
+		return fmt.Errorf(
+			"Repository config file '%s' cannot set '%s', set it in the user config file or environment variables",
+			path, key,
+		)
+	}
+
+	return parseConfigFile(path, data)
+}
+
+// findNotAllowedKey returns the first setting of the config file missing in allowedKeys
+func findNotAllowedKey(data []byte, allowedKeys map[string][]string) (string, error) {
+	var sections map[string]json.RawMessage
+	err := json.Unmarshal(data, &sections)
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range getSortedKeys(sections) {
+		sectionKeys, ok := allowedKeys[name]
+		if !ok {
+			return name, nil
+		}
+		if sectionKeys == nil {
+			continue
+		}
+
+		var section map[string]json.RawMessage
+		err = json.Unmarshal(sections[name], &section)
+		if err != nil {
+			return "", fmt.Errorf("'%s' should be an object: %s", name, err.Error())
+		}
+		for _, key := range getSortedKeys(section) {
+			if !containsString(sectionKeys, key) {
+				return name + "." + key, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func getSortedKeys(values map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func parseConfigFile(path string, data []byte) error {
+	err := json.Unmarshal(data, &Options)
+	if err != nil {
+		return fmt.Errorf("Failed to parse config file '%s': %s", path, err.Error())
+	}
+
+	switch Options.CommitMessage.IssueKeysPosition {
+	case "", git.IssueKeysPrefix, git.IssueKeysSuffix:
+	default:
+		return fmt.Errorf(
+			"Invalid commitMessage.issueKeysPosition '%s' in config file '%s', use prefix or suffix",
+			Options.CommitMessage.IssueKeysPosition, path,
+		)
+	}
+
+	return nil
+}
+
+// findRepositoryConfigFile looks for .got/config.json in the current directory and its parents
+func findRepositoryConfigFile() (string, error) {
+	return findRepositoryFile(configFileName)
+}
+
+// findRepositoryFile looks for the file or directory in .got directory of the current directory and its parents
+func findRepositoryFile(name string) (string, error) {
+	directory, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("Failed to get current directory: %s", err.Error())
+	}
+
+	for {
+		path := filepath.Join(directory, repositoryConfigDirectory, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+
+		parentDirectory := filepath.Dir(directory)
+		if parentDirectory == directory {
+			return "", nil
+		}
+		directory = parentDirectory
+	}
+}
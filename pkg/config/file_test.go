@@ -0,0 +1,63 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadRepositoryConfigFile(t *testing.T) {
+	directory, err := ioutil.TempDir("", "got-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	savedOptions := Options
+	defer func() { Options = savedOptions }()
+
+	tests := []struct {
+		content   string
+		wantError string
+	}{
+		{`{"issueBranchSeparator": "-", "prePush": {"checkIssueStatus": true}, "jira": {"projectCode": "PC"}}`, ""},
+		{`{"branchCreation": {"labels": ["team"]}, "baseBranch": {"default": "origin/main"}}`, ""},
+		{`{"jira": {"projectCode": "PC", "apiEndpoint": "https://jira.example.com"}}`, "jira.apiEndpoint"},
+		{`{"jira": {"apiKey": "key", "email": "me@example.com"}}`, "jira.apiKey"},
+		{`{"timeout": "1s"}`, "timeout"},
+		{`{"commitMessage": {"issueKeysPosition": "suffix"}}`, ""},
+		{`{"commitMessage": {"issueKeysPosition": "middle"}}`, "middle"},
+		{`{"forge": {"type": "gitlab"}}`, ""},
+		{`{"forge": {"type": "github", "hosts": {"github.com": {"apiUrl": "https://forge.example.com"}}}}`, "forge.hosts"},
+		{`{"forge": {"hosts": {"github.com": {"token": "secret"}}}}`, "forge.hosts"},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(directory, "config.json")
+		if err := ioutil.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		Options = savedOptions
+		err := readRepositoryConfigFile(path)
+		if tt.wantError == "" {
+			if err != nil {
+				t.Errorf("readRepositoryConfigFile of %s returned error %+v", tt.content, err)
+			}
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), "'"+tt.wantError+"'") {
+			t.Errorf("readRepositoryConfigFile of %s returned error %+v, want error about '%s'", tt.content, err, tt.wantError)
+		}
+		if Options.Jira.APIEndPoint != savedOptions.Jira.APIEndPoint || Options.Jira.APIKey != savedOptions.Jira.APIKey {
+			t.Errorf("readRepositoryConfigFile of %s changed Jira credentials", tt.content)
+		}
+		if !reflect.DeepEqual(Options.Forge.Hosts, savedOptions.Forge.Hosts) {
+			t.Errorf("readRepositoryConfigFile of %s changed forge settings", tt.content)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"got/pkg/git"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	issueTemplatesDirectory = "templates"
+	issueTemplateExtension  = ".md"
+	frontMatterDelimiter    = "---"
+)
+
+// IssueTemplate is a type for repository template of Jira issues created with -cj -template
+type IssueTemplate struct {
+	Name       string
+	IssueType  string
+	Labels     []string
+	Components []string
+	Priority   string
+	// Branch is a pattern of the issue branch name with {key}, {summary} and {type} placeholders
+	Branch string
+	// Description is a Markdown body of the template
+	Description string
+}
+
+// LoadIssueTemplate reads .got/templates/<name>.md of the repository.
+// The template starts with front matter setting type, labels, components, priority and branch
+// of the issue, the rest of the file is the issue description. The branch pattern is checked
+// with the configured separator and project code.
+func LoadIssueTemplate(name string) (IssueTemplate, error) {
+	templatesDirectory, err := findRepositoryFile(issueTemplatesDirectory)
+	if err != nil {
+		return IssueTemplate{}, err
+	}
+	if templatesDirectory == "" {
+		return IssueTemplate{}, fmt.Errorf(
+			"Issue template '%s' is not found, repository has no %s directory",
+			name, filepath.Join(repositoryConfigDirectory, issueTemplatesDirectory),
+		)
+	}
+
+	path := filepath.Join(templatesDirectory, name+issueTemplateExtension)
+	content, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return IssueTemplate{}, fmt.Errorf(
+			"Issue template '%s' is not found in %s, available templates: %s",
+			name, templatesDirectory, strings.Join(listIssueTemplates(templatesDirectory), ", "),
+		)
+	}
+	if err != nil {
+		return IssueTemplate{}, fmt.Errorf("Failed to read issue template '%s': %s", path, err.Error())
+	}
+
+	template, err := ParseIssueTemplate(string(content))
+	if err != nil {
+		return IssueTemplate{}, fmt.Errorf("Failed to parse issue template '%s': %s", path, err.Error())
+	}
+	if template.Branch != "" {
+		namer := git.BranchNamer{Separator: Options.IssueBranchSeparator, ProjectCode: Options.Jira.ProjectCode}
+		if err := namer.ValidateBranchNamePattern(template.Branch); err != nil {
+			return IssueTemplate{}, fmt.Errorf("Invalid branch of issue template '%s': %s", path, err.Error())
+		}
+	}
+	template.Name = name
+
+	return template, nil
+}
+
+func listIssueTemplates(templatesDirectory string) []string {
+	paths, _ := filepath.Glob(filepath.Join(templatesDirectory, "*"+issueTemplateExtension))
+
+	var names []string
+	for _, path := range paths {
+		names = append(names, strings.TrimSuffix(filepath.Base(path), issueTemplateExtension))
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseIssueTemplate parses issue template with optional front matter.
+// Front matter supports 'key: value' lines, lists written as '[a, b]' or as '- item' lines and '#' comments.
+func ParseIssueTemplate(content string) (IssueTemplate, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var template IssueTemplate
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != frontMatterDelimiter {
+		template.Description = strings.TrimSpace(content)
+		return template, nil
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == frontMatterDelimiter {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		return IssueTemplate{}, errors.New("front matter is not closed with '---'")
+	}
+
+	values, err := parseFrontMatter(lines[1:end])
+	if err != nil {
+		return IssueTemplate{}, err
+	}
+	for key, value := range values {
+		switch key {
+		case "type":
+			template.IssueType, err = getFrontMatterString(key, value)
+		case "priority":
+			template.Priority, err = getFrontMatterString(key, value)
+		case "branch":
+			template.Branch, err = getFrontMatterString(key, value)
+		case "labels":
+			template.Labels = value
+		case "components":
+			template.Components = value
+		default:
+			err = fmt.Errorf("unknown front matter key '%s', use type, labels, components, priority or branch", key)
+		}
+		if err != nil {
+			return IssueTemplate{}, err
+		}
+	}
+	template.Description = strings.TrimSpace(strings.Join(lines[end+1:], "\n"))
+
+	return template, nil
+}
+
+// parseFrontMatter returns values of front matter keys, single values are returned as lists with one element
+func parseFrontMatter(lines []string) (map[string][]string, error) {
+	values := map[string][]string{}
+	listKey := ""
+	for i, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if trimmedLine == "" || strings.HasPrefix(trimmedLine, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmedLine, "- ") || trimmedLine == "-" {
+			if listKey == "" {
+				return nil, fmt.Errorf("line %d: list item '%s' does not belong to a key", i+2, trimmedLine)
+			}
+			if item := unquoteFrontMatterValue(strings.TrimPrefix(trimmedLine, "-")); item != "" {
+				values[listKey] = append(values[listKey], item)
+			}
+			continue
+		}
+
+		separator := strings.Index(trimmedLine, ":")
+		if separator <= 0 {
+			return nil, fmt.Errorf("line %d: expected 'key: value', got '%s'", i+2, trimmedLine)
+		}
+		key := strings.ToLower(strings.TrimSpace(trimmedLine[:separator]))
+		value := strings.TrimSpace(trimmedLine[separator+1:])
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key '%s'", i+2, key)
+		}
+
+		listKey = ""
+		switch {
+		case value == "":
+			listKey = key
+			values[key] = nil
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			values[key] = []string{}
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = unquoteFrontMatterValue(item); item != "" {
+					values[key] = append(values[key], item)
+				}
+			}
+		default:
+			values[key] = []string{unquoteFrontMatterValue(value)}
+		}
+	}
+
+	return values, nil
+}
+
+func getFrontMatterString(key string, value []string) (string, error) {
+	if len(value) > 1 {
+		return "", fmt.Errorf("front matter key '%s' should have a single value", key)
+	}
+	if len(value) == 0 {
+		return "", nil
+	}
+	return value[0], nil
+}
+
+func unquoteFrontMatterValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
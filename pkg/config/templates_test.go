@@ -0,0 +1,114 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseIssueTemplate(t *testing.T) {
+	template, err := ParseIssueTemplate(strings.Join([]string{
+		"---",
+		"# bug reports",
+		"type: Bug",
+		"labels: [bug, 'needs-triage']",
+		"components:",
+		"  - API",
+		"  - \"Billing Service\"",
+		"priority: High",
+		"branch: bugfix/{key}/{summary}",
+		"---",
+		"",
+		"## Steps to reproduce",
+		"",
+	}, "\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := IssueTemplate{
+		IssueType:   "Bug",
+		Labels:      []string{"bug", "needs-triage"},
+		Components:  []string{"API", "Billing Service"},
+		Priority:    "High",
+		Branch:      "bugfix/{key}/{summary}",
+		Description: "## Steps to reproduce",
+	}
+	if !reflect.DeepEqual(template, want) {
+		t.Errorf("ParseIssueTemplate returned %+v, want %+v", template, want)
+	}
+
+	template, err = ParseIssueTemplate("Description only\n---\n")
+	if err != nil || template.Description != "Description only\n---" || template.IssueType != "" {
+		t.Errorf("ParseIssueTemplate without front matter returned %+v, %v", template, err)
+	}
+
+	errorTests := map[string]string{
+		"---\ntype: Bug\n":                  "front matter is not closed",
+		"---\nassignee: me\n---\n":          "unknown front matter key 'assignee'",
+		"---\ntype: [Bug, Task]\n---\n":     "key 'type' should have a single value",
+		"---\n- API\n---\n":                 "line 2: list item '- API' does not belong to a key",
+		"---\ntype: Bug\nType: Task\n---\n": "line 3: duplicate key 'type'",
+		"---\ncomponents API\n---\n":        "line 2: expected 'key: value'",
+	}
+	for content, wantErrMsg := range errorTests {
+		_, err := ParseIssueTemplate(content)
+		if err == nil || !strings.Contains(err.Error(), wantErrMsg) {
+			t.Errorf("ParseIssueTemplate for %q returned error %v, want %q", content, err, wantErrMsg)
+		}
+	}
+}
+
+func TestLoadIssueTemplate(t *testing.T) {
+	directory := t.TempDir()
+	templatesDirectory := filepath.Join(directory, repositoryConfigDirectory, issueTemplatesDirectory)
+	if err := os.MkdirAll(templatesDirectory, 0755); err != nil {
+		t.Fatal(err)
+	}
+	templates := map[string]string{
+		"bug.md":     "---\ntype: Bug\nbranch: bugfix/{key}/{summary}\n---\nSteps",
+		"feature.md": "Goal",
+		"story.md":   "---\nbranch: story-{key}-{summary}\n---\n",
+	}
+	for name, content := range templates {
+		if err := ioutil.WriteFile(filepath.Join(templatesDirectory, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	subdirectory := filepath.Join(directory, "pkg")
+	if err := os.Mkdir(subdirectory, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(workingDirectory) })
+	if err := os.Chdir(subdirectory); err != nil {
+		t.Fatal(err)
+	}
+
+	savedOptions := Options
+	t.Cleanup(func() { Options = savedOptions })
+	Options.IssueBranchSeparator = "/"
+	Options.Jira.ProjectCode = "PC"
+
+	template, err := LoadIssueTemplate("bug")
+	if err != nil || template.Name != "bug" || template.IssueType != "Bug" || template.Description != "Steps" {
+		t.Errorf("LoadIssueTemplate returned %+v, %v, want bug template", template, err)
+	}
+
+	_, err = LoadIssueTemplate("story")
+	if err == nil || !strings.Contains(err.Error(), "should separate {key} from the rest of the name with '/'") {
+		t.Errorf("LoadIssueTemplate for template with invalid branch returned error %v, want branch error", err)
+	}
+
+	_, err = LoadIssueTemplate("task")
+	if err == nil || !strings.Contains(err.Error(), "available templates: bug, feature, story") {
+		t.Errorf("LoadIssueTemplate for missing template returned error %v, want available templates", err)
+	}
+}
@@ -0,0 +1,81 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type bitbucket struct {
+	client apiClient
+}
+
+// newBitbucket returns client of Bitbucket Cloud REST API 2.0
+func newBitbucket(settings Settings) *bitbucket {
+	return &bitbucket{client: newAPIClient(Bitbucket, settings, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+settings.Token)
+	})}
+}
+
+func (forge *bitbucket) Type() Type {
+	return Bitbucket
+}
+
+func (forge *bitbucket) repositoryPath() string {
+	return fmt.Sprintf(
+		"/repositories/%s/%s", url.PathEscape(forge.client.settings.Owner), url.PathEscape(forge.client.settings.Repository),
+	)
+}
+
+func (forge *bitbucket) BranchURL(branchName string) string {
+	return getRepositoryWebURL(forge.client.settings) + "/branch/" + escapeBranchName(branchName)
+}
+
+func (forge *bitbucket) DefaultBranch(ctx context.Context) (string, error) {
+	var repository struct {
+		MainBranch struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}
+	err := forge.client.sendRequest(ctx, "GET", forge.repositoryPath(), nil, &repository)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get Bitbucket repository: %w", err)
+	}
+
+	return repository.MainBranch.Name, nil
+}
+
+type bitbucketBranchReference struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+}
+
+func (forge *bitbucket) CreatePullRequest(ctx context.Context, pullRequest PullRequest) (CreatedPullRequest, error) {
+	var body struct {
+		Title       string                   `json:"title"`
+		Description string                   `json:"description"`
+		Source      bitbucketBranchReference `json:"source"`
+		Destination bitbucketBranchReference `json:"destination"`
+	}
+	body.Title = pullRequest.Title
+	body.Description = pullRequest.Body
+	body.Source.Branch.Name = pullRequest.SourceBranch
+	body.Destination.Branch.Name = pullRequest.TargetBranch
+
+	var response struct {
+		ID    int `json:"id"`
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	}
+	err := forge.client.sendRequest(ctx, "POST", forge.repositoryPath()+"/pullrequests", body, &response)
+	if err != nil {
+		return CreatedPullRequest{}, fmt.Errorf("Failed to create Bitbucket pull request: %w", err)
+	}
+
+	return CreatedPullRequest{Number: response.ID, URL: response.Links.HTML.Href}, nil
+}
@@ -0,0 +1,321 @@
+// Package forge creates pull requests and links to branches on git hosting services: GitHub, GitLab and Bitbucket Cloud.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Type is a type for enum values of supported git hosting services
+type Type string
+
+// GitHub is a holder of forge type name
+const (
+	GitHub    Type = "github"
+	GitLab    Type = "gitlab"
+	Bitbucket Type = "bitbucket"
+)
+
+// Settings is a configuration of forge client
+type Settings struct {
+	// Type is a git hosting service, it is detected from the host when empty
+	Type Type
+	// APIURL is a base URL of REST API. When it is empty, API of the public service is used for its host,
+	// requests to other hosts fail
+	APIURL string
+	// WebURL is a base URL of web pages of the forge, 'https://' and the host is used when it is empty
+	WebURL string
+	// Host is a server name of the repository remote URL
+	Host string
+	// Owner is a user, organisation, group or workspace of the repository
+	Owner string
+	// Repository is a name of the repository
+	Repository string
+	// Token is a personal or repository access token of the host, it is sent only to APIURL or to API of the public service
+	Token string
+	// Username is sent with the token using basic authentication, e.g. for Bitbucket app passwords
+	Username string
+	// Timeout limits duration of every request, zero disables the timeout
+	Timeout time.Duration
+}
+
+// PullRequest is a type for pull or merge request to be created
+type PullRequest struct {
+	Title        string
+	Body         string
+	SourceBranch string
+	TargetBranch string
+}
+
+// CreatedPullRequest is a type for pull or merge request created on the forge
+type CreatedPullRequest struct {
+	Number int
+	URL    string
+}
+
+// Forge is a git hosting service pull requests are created on
+type Forge interface {
+	// Type returns type of the git hosting service
+	Type() Type
+	// DefaultBranch returns default branch of the repository pull requests are merged into
+	DefaultBranch(ctx context.Context) (string, error)
+	// CreatePullRequest creates pull request from the source branch to the target branch
+	CreatePullRequest(ctx context.Context, pullRequest PullRequest) (CreatedPullRequest, error)
+	// BranchURL returns URL of the branch page of the repository
+	BranchURL(branchName string) string
+}
+
+// ErrUnknownHost is returned when git hosting service cannot be detected from the host name
+var ErrUnknownHost = errors.New("Cannot detect git hosting service")
+
+// publicServices are hosts and API URLs of public git hosting services. Only these hosts are detected,
+// type and API URL of other hosts are configured, so that tokens are not sent to look-alike hosts.
+var publicServices = []struct {
+	forgeType Type
+	host      string
+	apiURL    string
+}{
+	{GitHub, "github.com", "https://api.github.com"},
+	{GitLab, "gitlab.com", "https://gitlab.com/api/v4"},
+	{Bitbucket, "bitbucket.org", "https://api.bitbucket.org/2.0"},
+}
+
+// New returns forge client of the configured or detected type
+func New(settings Settings) (Forge, error) {
+	forgeType := settings.Type
+	if forgeType == "" {
+		var err error
+		forgeType, err = DetectType(settings.Host)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if settings.Owner == "" || settings.Repository == "" {
+		return nil, fmt.Errorf("Repository owner and name are required to create pull requests on %s", forgeType)
+	}
+
+	switch forgeType {
+	case GitHub:
+		return newGitHub(settings), nil
+	case GitLab:
+		return newGitLab(settings), nil
+	case Bitbucket:
+		return newBitbucket(settings), nil
+	default:
+		return nil, fmt.Errorf("Unknown forge type '%s', use github, gitlab or bitbucket", forgeType)
+	}
+}
+
+// DetectType returns type of public git hosting service by its host name, e.g. 'gitlab.com' is GitLab.
+// Self-hosted servers are not detected.
+func DetectType(host string) (Type, error) {
+	for _, service := range publicServices {
+		if strings.EqualFold(host, service.host) {
+			return service.forgeType, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w of host '%s', set forge.type in config", ErrUnknownHost, host)
+}
+
+// getAPIURL returns configured API URL or API URL of the public service when the host is its host
+func getAPIURL(forgeType Type, settings Settings) (string, error) {
+	if settings.APIURL != "" {
+		return settings.APIURL, nil
+	}
+	for _, service := range publicServices {
+		if service.forgeType == forgeType && strings.EqualFold(settings.Host, service.host) {
+			return service.apiURL, nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"API URL of %s host '%s' is not configured, set forge.hosts.%s.apiUrl in the user config",
+		forgeType, settings.Host, settings.Host,
+	)
+}
+
+// getRepositoryWebURL returns URL of the repository web page
+func getRepositoryWebURL(settings Settings) string {
+	webURL := settings.WebURL
+	if webURL == "" {
+		webURL = "https://" + settings.Host
+	}
+	return strings.TrimRight(webURL, "/") + "/" + settings.Owner + "/" + settings.Repository
+}
+
+// escapeBranchName escapes branch name for URL paths keeping slashes of its segments
+func escapeBranchName(branchName string) string {
+	segments := strings.Split(branchName, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// Error is an error response of forge API with messages decoded from the response body
+type Error struct {
+	StatusCode int
+	Messages   []string
+}
+
+func (err *Error) Error() string {
+	message := fmt.Sprintf("%d %s", err.StatusCode, getStatusDescription(err.StatusCode))
+	if len(err.Messages) == 0 {
+		return message
+	}
+	return fmt.Sprintf("%s: %s", message, strings.Join(err.Messages, "; "))
+}
+
+func getStatusDescription(statusCode int) string {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return "bad credentials, check forge token"
+	case http.StatusForbidden:
+		return "access denied"
+	case http.StatusNotFound:
+		return "not found"
+	}
+	return strings.ToLower(http.StatusText(statusCode))
+}
+
+// apiClient sends JSON requests to REST API of the forge
+type apiClient struct {
+	baseURL string
+	// err is returned by requests when API URL of the host is unknown
+	err        error
+	settings   Settings
+	httpClient *http.Client
+	// setAuthorization adds forge specific authorization header to the request
+	setAuthorization func(req *http.Request)
+}
+
+func newAPIClient(forgeType Type, settings Settings, setAuthorization func(req *http.Request)) apiClient {
+	baseURL, err := getAPIURL(forgeType, settings)
+	return apiClient{
+		baseURL:          strings.TrimRight(baseURL, "/"),
+		err:              err,
+		settings:         settings,
+		httpClient:       &http.Client{},
+		setAuthorization: setAuthorization,
+	}
+}
+
+// sendRequest sends request with JSON body and decodes JSON response into result
+func (client apiClient) sendRequest(
+	ctx context.Context, method string, path string, body interface{}, result interface{},
+) error {
+	if client.err != nil {
+		return client.err
+	}
+
+	if client.settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.settings.Timeout)
+		defer cancel()
+	}
+
+	var requestBody []byte
+	if body != nil {
+		var err error
+		requestBody, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, client.baseURL+path, bytes.NewReader(requestBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if client.settings.Username != "" {
+		req.SetBasicAuth(client.settings.Username, client.settings.Token)
+	} else if client.settings.Token != "" {
+		client.setAuthorization(req)
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bodyText, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("Failed to parse reponse body: %s", err.Error())
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &Error{StatusCode: resp.StatusCode, Messages: getErrorMessages(bodyText)}
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(bodyText, result)
+}
+
+// getErrorMessages returns messages of GitHub, GitLab and Bitbucket error responses
+func getErrorMessages(bodyText []byte) []string {
+	var body interface{}
+	if json.Unmarshal(bodyText, &body) != nil {
+		if message := strings.TrimSpace(string(bodyText)); message != "" && !strings.HasPrefix(message, "<") {
+			return []string{message}
+		}
+		return nil
+	}
+
+	object, _ := body.(map[string]interface{})
+	var messages []string
+	// GitHub: {"message": "...", "errors": [{"message": "..."}]}, GitLab: {"message": "..." | [...] | {...}},
+	// Bitbucket: {"error": {"message": "..."}}
+	messages = append(messages, collectMessages(object["message"])...)
+	messages = append(messages, collectMessages(object["error"])...)
+	messages = append(messages, collectMessages(object["errors"])...)
+
+	return messages
+}
+
+func collectMessages(value interface{}) []string {
+	switch typedValue := value.(type) {
+	case string:
+		return []string{typedValue}
+	case []interface{}:
+		var messages []string
+		for _, item := range typedValue {
+			messages = append(messages, collectMessages(item)...)
+		}
+		return messages
+	case map[string]interface{}:
+		if message, ok := typedValue["message"]; ok {
+			return collectMessages(message)
+		}
+		fields := make([]string, 0, len(typedValue))
+		for field := range typedValue {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		var messages []string
+		for _, field := range fields {
+			for _, message := range collectMessages(typedValue[field]) {
+				messages = append(messages, fmt.Sprintf("%s %s", field, message))
+			}
+		}
+		return messages
+	}
+	return nil
+}
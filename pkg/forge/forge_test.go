@@ -0,0 +1,259 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recordedRequest is a request received by the test server
+type recordedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   map[string]interface{}
+}
+
+// newTestServer returns server responding with JSON bodies of the paths and recording received requests
+func newTestServer(t *testing.T, responses map[string]string) (*httptest.Server, *[]recordedRequest) {
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := recordedRequest{Method: r.Method, Path: r.URL.EscapedPath(), Header: r.Header}
+		if bodyText, _ := ioutil.ReadAll(r.Body); len(bodyText) > 0 {
+			json.Unmarshal(bodyText, &request.Body)
+		}
+		requests = append(requests, request)
+
+		response, ok := responses[r.Method+" "+request.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestGitHub(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{
+		"GET /repos/octo/got":        `{"default_branch": "main"}`,
+		"POST /repos/octo/got/pulls": `{"number": 7, "html_url": "https://github.com/octo/got/pull/7"}`,
+	})
+	client, err := New(Settings{APIURL: server.URL, Host: "github.com", Owner: "octo", Repository: "got", Token: "secret"})
+	if err != nil || client.Type() != GitHub {
+		t.Fatalf("New returned %+v, %+v, want GitHub client", client, err)
+	}
+
+	ctx := context.Background()
+	if branch, err := client.DefaultBranch(ctx); branch != "main" || err != nil {
+		t.Errorf("DefaultBranch returned %+v, %+v, want main", branch, err)
+	}
+	pullRequest, err := client.CreatePullRequest(ctx, PullRequest{Title: "PC-1: Fix", Body: "Text", SourceBranch: "PC-1/fix", TargetBranch: "main"})
+	if err != nil || pullRequest.Number != 7 || pullRequest.URL != "https://github.com/octo/got/pull/7" {
+		t.Errorf("CreatePullRequest returned %+v, %+v, want pull request 7", pullRequest, err)
+	}
+
+	request := (*requests)[1]
+	wantBody := map[string]interface{}{"title": "PC-1: Fix", "body": "Text", "head": "PC-1/fix", "base": "main"}
+	if !reflect.DeepEqual(request.Body, wantBody) || request.Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("CreatePullRequest sent %+v, want body %+v and bearer token", request, wantBody)
+	}
+}
+
+func TestGitLab(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{
+		"GET /projects/group%2Fsub%2Fgot":                 `{"default_branch": "develop"}`,
+		"POST /projects/group%2Fsub%2Fgot/merge_requests": `{"iid": 3, "web_url": "https://gitlab.com/group/sub/got/-/merge_requests/3"}`,
+	})
+	client, err := New(Settings{APIURL: server.URL + "/", Host: "gitlab.com", Owner: "group/sub", Repository: "got", Token: "secret"})
+	if err != nil || client.Type() != GitLab {
+		t.Fatalf("New returned %+v, %+v, want GitLab client", client, err)
+	}
+
+	ctx := context.Background()
+	if branch, err := client.DefaultBranch(ctx); branch != "develop" || err != nil {
+		t.Errorf("DefaultBranch returned %+v, %+v, want develop", branch, err)
+	}
+	pullRequest, err := client.CreatePullRequest(ctx, PullRequest{Title: "PC-1: Fix", Body: "Text", SourceBranch: "PC-1/fix", TargetBranch: "develop"})
+	if err != nil || pullRequest.Number != 3 {
+		t.Errorf("CreatePullRequest returned %+v, %+v, want merge request 3", pullRequest, err)
+	}
+
+	request := (*requests)[1]
+	wantBody := map[string]interface{}{
+		"title": "PC-1: Fix", "description": "Text", "source_branch": "PC-1/fix", "target_branch": "develop",
+	}
+	if !reflect.DeepEqual(request.Body, wantBody) || request.Header.Get("PRIVATE-TOKEN") != "secret" {
+		t.Errorf("CreatePullRequest sent %+v, want body %+v and private token", request, wantBody)
+	}
+}
+
+func TestBitbucket(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{
+		"GET /repositories/team/got":               `{"mainbranch": {"name": "master"}}`,
+		"POST /repositories/team/got/pullrequests": `{"id": 12, "links": {"html": {"href": "https://bitbucket.org/team/got/pull-requests/12"}}}`,
+	})
+	client, err := New(Settings{APIURL: server.URL, Host: "bitbucket.org", Owner: "team", Repository: "got", Token: "secret", Username: "ann"})
+	if err != nil || client.Type() != Bitbucket {
+		t.Fatalf("New returned %+v, %+v, want Bitbucket client", client, err)
+	}
+
+	ctx := context.Background()
+	if branch, err := client.DefaultBranch(ctx); branch != "master" || err != nil {
+		t.Errorf("DefaultBranch returned %+v, %+v, want master", branch, err)
+	}
+	pullRequest, err := client.CreatePullRequest(ctx, PullRequest{Title: "PC-1: Fix", Body: "Text", SourceBranch: "PC-1/fix", TargetBranch: "master"})
+	if err != nil || pullRequest.Number != 12 || pullRequest.URL != "https://bitbucket.org/team/got/pull-requests/12" {
+		t.Errorf("CreatePullRequest returned %+v, %+v, want pull request 12", pullRequest, err)
+	}
+
+	request := (*requests)[1]
+	wantBody := map[string]interface{}{
+		"title":       "PC-1: Fix",
+		"description": "Text",
+		"source":      map[string]interface{}{"branch": map[string]interface{}{"name": "PC-1/fix"}},
+		"destination": map[string]interface{}{"branch": map[string]interface{}{"name": "master"}},
+	}
+	if username, password, ok := (&http.Request{Header: request.Header}).BasicAuth(); !ok || username != "ann" || password != "secret" {
+		t.Errorf("CreatePullRequest sent authorization %q, want basic authentication", request.Header.Get("Authorization"))
+	}
+	if !reflect.DeepEqual(request.Body, wantBody) {
+		t.Errorf("CreatePullRequest sent %+v, want %+v", request.Body, wantBody)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		settings Settings
+		wantType Type
+		wantErr  bool
+	}{
+		{Settings{Host: "github.com", Owner: "o", Repository: "r"}, GitHub, false},
+		{Settings{Host: "GitLab.com", Owner: "o", Repository: "r"}, GitLab, false},
+		{Settings{Host: "bitbucket.org", Owner: "o", Repository: "r"}, Bitbucket, false},
+		{Settings{Type: GitLab, Host: "git.example.com", Owner: "o", Repository: "r"}, GitLab, false},
+		{Settings{Host: "git.example.com", Owner: "o", Repository: "r"}, "", true},
+		{Settings{Host: "github.evil.example", Owner: "o", Repository: "r"}, "", true},
+		{Settings{Host: "mygitlab-mirror.net", Owner: "o", Repository: "r"}, "", true},
+		{Settings{Host: "bitbucket.corp", Owner: "o", Repository: "r"}, "", true},
+		{Settings{Type: "gitea", Host: "git.example.com", Owner: "o", Repository: "r"}, "", true},
+		{Settings{Host: "github.com", Repository: "r"}, "", true},
+	}
+
+	for _, tt := range tests {
+		client, err := New(tt.settings)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("New for %+v returned %+v, want error", tt.settings, client)
+			}
+			continue
+		}
+		if err != nil || client.Type() != tt.wantType {
+			t.Errorf("New for %+v returned %+v, %+v, want %s", tt.settings, client, err, tt.wantType)
+		}
+	}
+
+}
+
+func TestNew_SendsTokenOnlyToAPIOfHost(t *testing.T) {
+	tests := []struct {
+		settings   Settings
+		wantAPIURL string
+	}{
+		{Settings{Host: "github.com"}, "https://api.github.com"},
+		{Settings{Host: "gitlab.com"}, "https://gitlab.com/api/v4"},
+		{Settings{Host: "bitbucket.org"}, "https://api.bitbucket.org/2.0"},
+		{Settings{Type: GitHub, Host: "github.example.com", APIURL: "https://github.example.com/api/v3/"}, "https://github.example.com/api/v3"},
+		{Settings{Type: GitHub, Host: "github.evil.example"}, ""},
+		{Settings{Type: GitLab, Host: "github.com"}, ""},
+		{Settings{Type: GitHub, Host: "gitlab.com"}, ""},
+		{Settings{Type: Bitbucket, Host: "bitbucket.corp"}, ""},
+	}
+
+	for _, tt := range tests {
+		tt.settings.Owner, tt.settings.Repository, tt.settings.Token = "o", "r", "secret"
+		client, err := New(tt.settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var apiClient apiClient
+		switch typedClient := client.(type) {
+		case *gitHub:
+			apiClient = typedClient.client
+		case *gitLab:
+			apiClient = typedClient.client
+		case *bitbucket:
+			apiClient = typedClient.client
+		}
+		if tt.wantAPIURL != "" && (apiClient.err != nil || apiClient.baseURL != tt.wantAPIURL) {
+			t.Errorf("New for %+v uses API URL %s, %+v, want %s", tt.settings, apiClient.baseURL, apiClient.err, tt.wantAPIURL)
+		}
+		if tt.wantAPIURL == "" {
+			_, err := client.DefaultBranch(context.Background())
+			if err == nil || !strings.Contains(err.Error(), "is not configured") {
+				t.Errorf("DefaultBranch for %+v returned error %+v, want API URL error", tt.settings, err)
+			}
+		}
+	}
+}
+
+func TestBranchURL(t *testing.T) {
+	tests := []struct {
+		settings Settings
+		want     string
+	}{
+		{Settings{Host: "github.com", Owner: "octo", Repository: "got"}, "https://github.com/octo/got/tree/PC-1/fix_%23login"},
+		{
+			Settings{Type: GitLab, Host: "gitlab.example.com", WebURL: "http://gitlab.example.com:8080/", Owner: "group/sub", Repository: "got"},
+			"http://gitlab.example.com:8080/group/sub/got/-/tree/PC-1/fix_%23login",
+		},
+		{Settings{Host: "bitbucket.org", Owner: "team", Repository: "got"}, "https://bitbucket.org/team/got/branch/PC-1/fix_%23login"},
+	}
+
+	for _, tt := range tests {
+		client, err := New(tt.settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if branchURL := client.BranchURL("PC-1/fix_#login"); branchURL != tt.want {
+			t.Errorf("BranchURL for %s returned %s, want %s", client.Type(), branchURL, tt.want)
+		}
+	}
+}
+
+func TestErrorMessages(t *testing.T) {
+	tests := []struct {
+		response string
+		want     string
+	}{
+		{
+			`{"message": "Validation Failed", "errors": [{"resource": "PullRequest", "message": "A pull request already exists"}]}`,
+			"422 unprocessable entity: Validation Failed; A pull request already exists",
+		},
+		{`{"message": {"source_branch": ["is invalid"], "base": ["missing"]}}`, "422 unprocessable entity: base missing; source_branch is invalid"},
+		{`{"type": "error", "error": {"message": "Branch not found"}}`, "422 unprocessable entity: Branch not found"},
+		{`<html>Error</html>`, "422 unprocessable entity"},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(tt.response))
+		}))
+		err := newAPIClient(GitHub, Settings{APIURL: server.URL}, nil).sendRequest(context.Background(), "GET", "/", nil, nil)
+		server.Close()
+
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("sendRequest for response %s returned %+v, want %s", tt.response, err, tt.want)
+		}
+	}
+}
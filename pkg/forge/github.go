@@ -0,0 +1,62 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type gitHub struct {
+	client apiClient
+}
+
+// newGitHub returns client of GitHub REST API, GitHub Enterprise Server requires configured API URL
+func newGitHub(settings Settings) *gitHub {
+	return &gitHub{client: newAPIClient(GitHub, settings, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+settings.Token)
+	})}
+}
+
+func (forge *gitHub) Type() Type {
+	return GitHub
+}
+
+func (forge *gitHub) repositoryPath() string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(forge.client.settings.Owner), url.PathEscape(forge.client.settings.Repository))
+}
+
+func (forge *gitHub) BranchURL(branchName string) string {
+	return getRepositoryWebURL(forge.client.settings) + "/tree/" + escapeBranchName(branchName)
+}
+
+func (forge *gitHub) DefaultBranch(ctx context.Context) (string, error) {
+	var repository struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	err := forge.client.sendRequest(ctx, "GET", forge.repositoryPath(), nil, &repository)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get GitHub repository: %w", err)
+	}
+
+	return repository.DefaultBranch, nil
+}
+
+func (forge *gitHub) CreatePullRequest(ctx context.Context, pullRequest PullRequest) (CreatedPullRequest, error) {
+	body := map[string]string{
+		"title": pullRequest.Title,
+		"body":  pullRequest.Body,
+		"head":  pullRequest.SourceBranch,
+		"base":  pullRequest.TargetBranch,
+	}
+	var response struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	err := forge.client.sendRequest(ctx, "POST", forge.repositoryPath()+"/pulls", body, &response)
+	if err != nil {
+		return CreatedPullRequest{}, fmt.Errorf("Failed to create GitHub pull request: %w", err)
+	}
+
+	return CreatedPullRequest{Number: response.Number, URL: response.HTMLURL}, nil
+}
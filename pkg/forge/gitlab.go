@@ -0,0 +1,63 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type gitLab struct {
+	client apiClient
+}
+
+// newGitLab returns client of GitLab REST API v4, self-hosted servers require configured API URL
+func newGitLab(settings Settings) *gitLab {
+	return &gitLab{client: newAPIClient(GitLab, settings, func(req *http.Request) {
+		req.Header.Set("PRIVATE-TOKEN", settings.Token)
+	})}
+}
+
+func (forge *gitLab) Type() Type {
+	return GitLab
+}
+
+// projectPath returns path of the project referenced by URL encoded namespace and name, e.g. 'group%2Fsubgroup%2Frepo'
+func (forge *gitLab) projectPath() string {
+	return "/projects/" + url.PathEscape(forge.client.settings.Owner+"/"+forge.client.settings.Repository)
+}
+
+func (forge *gitLab) BranchURL(branchName string) string {
+	return getRepositoryWebURL(forge.client.settings) + "/-/tree/" + escapeBranchName(branchName)
+}
+
+func (forge *gitLab) DefaultBranch(ctx context.Context) (string, error) {
+	var project struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	err := forge.client.sendRequest(ctx, "GET", forge.projectPath(), nil, &project)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get GitLab project: %w", err)
+	}
+
+	return project.DefaultBranch, nil
+}
+
+func (forge *gitLab) CreatePullRequest(ctx context.Context, pullRequest PullRequest) (CreatedPullRequest, error) {
+	body := map[string]string{
+		"title":         pullRequest.Title,
+		"description":   pullRequest.Body,
+		"source_branch": pullRequest.SourceBranch,
+		"target_branch": pullRequest.TargetBranch,
+	}
+	var response struct {
+		IID    int    `json:"iid"`
+		WebURL string `json:"web_url"`
+	}
+	err := forge.client.sendRequest(ctx, "POST", forge.projectPath()+"/merge_requests", body, &response)
+	if err != nil {
+		return CreatedPullRequest{}, fmt.Errorf("Failed to create GitLab merge request: %w", err)
+	}
+
+	return CreatedPullRequest{Number: response.IID, URL: response.WebURL}, nil
+}
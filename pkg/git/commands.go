@@ -1,113 +1,499 @@
 package git
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// Returns name of repo from branch origin url
-func GetRepositoryName() (string, error) {
-	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
+// CheckoutBranch checkouts git branch by name
+func CheckoutBranch(ctx context.Context, branchName string) (string, error) {
+	output, err := runInterruptibleCommand(ctx, false, "checkout", branchName)
+	if err != nil {
+		formattedError := fmt.Errorf(fmt.Sprintf("Failed to switch to branch '%s'. Error: '%s'", branchName, err.Error()))
+		return string(output), formattedError
+	}
+
+	return string(output), nil
+}
+
+// CheckoutNewBranch creates and checks out new branch from start point, empty start point means current HEAD
+func CheckoutNewBranch(ctx context.Context, branchName string, startPoint string) ([]byte, error) {
+	args := []string{"checkout", "-b", branchName}
+	if startPoint != "" {
+		args = append(args, "--no-track", startPoint)
+	}
+	output, err := runInterruptibleCommand(ctx, false, args...)
+	if err != nil {
+		return output, fmt.Errorf(
+			fmt.Sprintf("Failed to create a new branch '%s'. Error: '%s'", branchName, err.Error()),
+		)
+	}
+
+	return output, nil
+}
+
+// UpdateCurrentBranchName updates current branch name
+func UpdateCurrentBranchName(ctx context.Context, branchName string) ([]byte, error) {
+	output, err := runInterruptibleCommand(ctx, false, "branch", "-m", branchName)
+	if err != nil {
+		return output, fmt.Errorf(
+			fmt.Sprintf("Failed to update branch name to '%s'. Error: '%s'", branchName, err.Error()),
+		)
+	}
+
+	return output, nil
+
+}
+
+// GetCurrentBranchName returns current git branch
+func GetCurrentBranchName(ctx context.Context) (string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "branch", "--show-current")
 	output, err := cmd.Output()
 	if err != nil {
 		return string(output), fmt.Errorf(
-			fmt.Sprintf("Failed get current git orgin url with error: '%s'", err.Error()),
+			fmt.Sprintf("Failed get current git branch with. Error: '%s'", err.Error()),
 		)
 	}
 
-	repoName := strings.Split(string(output), ".git")[0]
-	tempRepoName := strings.Split(repoName, "/")
+	return strings.TrimSuffix(string(output), "\n"), nil
+}
+
+// GetRepositoryRoot returns absolute path of the current repository working tree
+func GetRepositoryRoot(ctx context.Context) (string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Failed to get repository root directory. Error: '%s'", err.Error())
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetHooksDirectory returns path of the directory git reads hooks from
+func GetHooksDirectory(ctx context.Context) (string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--git-path", "hooks")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Failed to get git hooks directory. Error: '%s'", err.Error())
+	}
 
-	return 	tempRepoName[len(tempRepoName)-1], nil
+	return strings.TrimSpace(string(output)), nil
 }
 
-// FindBranchBySubstring finds branch
-func FindBranchBySubstring(substring string) (string, error) {
-	listAllBranchesCmd := exec.Command("git", "branch", "-a")
-	grepCmd := exec.Command("grep", substring)
-	var err error
-	pipe, err := listAllBranchesCmd.StdoutPipe()
-	defer pipe.Close()
+// ListWorktrees returns worktrees of the repository, the main worktree goes first
+func ListWorktrees(ctx context.Context) ([]Worktree, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "worktree", "list", "--porcelain")
+	output, err := cmd.Output()
 	if err != nil {
-		formattedError := fmt.Errorf(fmt.Sprintf("Failed to create pipe for grep '%s'. Error: '%s'", substring, err.Error()))
-		return "", formattedError
+		return nil, fmt.Errorf("Failed to list git worktrees. Error: '%s'", err.Error())
+	}
+
+	var worktrees []Worktree
+	for _, block := range strings.Split(strings.TrimSpace(string(output)), "\n\n") {
+		var worktree Worktree
+		for _, line := range strings.Split(block, "\n") {
+			name := strings.SplitN(line, " ", 2)[0]
+			value := strings.TrimPrefix(strings.TrimPrefix(line, name), " ")
+			switch name {
+			case "worktree":
+				worktree.Path = value
+			case "HEAD":
+				worktree.Head = value
+			case "branch":
+				worktree.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "bare":
+				worktree.Bare = true
+			case "detached":
+				worktree.Detached = true
+			case "prunable":
+				worktree.Prunable = true
+			}
+		}
+
+		if worktree.Path != "" {
+			worktrees = append(worktrees, worktree)
+		}
 	}
 
-	grepCmd.Stdin = pipe
+	return worktrees, nil
+}
 
-	err = listAllBranchesCmd.Start()
+// AddWorktree creates worktree at specified path for existing branch
+func AddWorktree(ctx context.Context, path string, branchName string) ([]byte, error) {
+	output, err := runInterruptibleCommand(ctx, true, "worktree", "add", path, branchName)
 	if err != nil {
-		formattedError := fmt.Errorf(fmt.Sprintf("Failed to start grep command for '%s'. Error: '%s'", substring, err.Error()))
-		return "", formattedError
+		return output, fmt.Errorf("Failed to create worktree '%s' for branch '%s'. Error: '%s'", path, branchName, err.Error())
 	}
 
-	output, _ := grepCmd.Output()
+	return output, nil
+}
+
+// AddWorktreeWithNewBranch creates a new branch from start point and worktree for it at specified path
+func AddWorktreeWithNewBranch(ctx context.Context, path string, branchName string, startPoint string) ([]byte, error) {
+	args := []string{"worktree", "add", "-b", branchName, path}
+	if startPoint != "" {
+		args = append(args, "--no-track", startPoint)
+	}
+	output, err := runInterruptibleCommand(ctx, true, args...)
 	if err != nil {
-		formattedError := fmt.Errorf(fmt.Sprintf("Failed to get result from grep for '%s'. Error: '%s'", substring, err.Error()))
-		return "", formattedError
+		return output, fmt.Errorf("Failed to create worktree '%s' with new branch '%s'. Error: '%s'", path, branchName, err.Error())
 	}
 
-	branches := strings.Split(string(output), "\n")
-	if len(branches) == 0 {
-		return "", nil
+	return output, nil
+}
+
+// RemoveWorktree removes worktree, worktrees with local changes are not removed
+func RemoveWorktree(ctx context.Context, path string) ([]byte, error) {
+	output, err := runInterruptibleCommand(ctx, true, "worktree", "remove", path)
+	if err != nil {
+		return output, fmt.Errorf("Failed to remove worktree '%s'. Error: '%s'", path, err.Error())
 	}
 
-	branch := strings.ReplaceAll(branches[0], "* ", "")
-	branch = strings.ReplaceAll(branch, "remotes/origin/", "")
-	branch = strings.ReplaceAll(branch, " ", "")
+	return output, nil
+}
+
+// PruneWorktrees removes information about worktrees which directories were deleted
+func PruneWorktrees(ctx context.Context) ([]byte, error) {
+	output, err := runInterruptibleCommand(ctx, true, "worktree", "prune", "--verbose")
+	if err != nil {
+		return output, fmt.Errorf("Failed to prune worktrees. Error: '%s'", err.Error())
+	}
 
-	return strings.TrimSpace(branch), nil
+	return output, nil
 }
 
-// CheckoutBranch checkouts git branch by name
-func CheckoutBranch(branchName string) (string, error) {
-	cmd := exec.Command("git", "checkout", branchName)
+// GetChangedFiles returns paths of tracked files with local changes
+func GetChangedFiles(ctx context.Context) ([]string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain", "--untracked-files=no")
 	output, err := cmd.Output()
 	if err != nil {
-		formattedError := fmt.Errorf(fmt.Sprintf("Failed to switch to branch '%s'. Error: '%s'", branchName, err.Error()))
-		return string(output), formattedError
+		return nil, fmt.Errorf("Failed to get git working tree status. Error: '%s'", err.Error())
 	}
 
-	return string(output), nil
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) > 3 {
+			files = append(files, line[3:])
+		}
+	}
+
+	return files, nil
+}
+
+// StashChanges stashes local changes with specified message
+func StashChanges(ctx context.Context, message string) ([]byte, error) {
+	output, err := runInterruptibleCommand(ctx, true, "stash", "push", "--message", message)
+	if err != nil {
+		return output, fmt.Errorf("Failed to stash local changes. Error: '%s'", err.Error())
+	}
+
+	return output, nil
 }
 
-// CheckoutNewBranch creates and checks out new branch
-func CheckoutNewBranch(branchName string) ([]byte, error) {
-	cmd := exec.Command("git", "checkout", "-b", branchName)
+// FindStash returns reference of the latest stash which message contains substring
+func FindStash(ctx context.Context, substring string) (string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "stash", "list", "--format=%gd %s")
 	output, err := cmd.Output()
 	if err != nil {
-		return output, fmt.Errorf(
-			fmt.Sprintf("Failed to create a new branch '%s'. Error: '%s'", branchName, err.Error()),
-		)
+		return "", fmt.Errorf("Failed to list stashes. Error: '%s'", err.Error())
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.Contains(line, substring) {
+			return strings.SplitN(line, " ", 2)[0], nil
+		}
+	}
+
+	return "", nil
+}
+
+// PopStash applies stash by reference and removes it from the stash list
+func PopStash(ctx context.Context, stashRef string) ([]byte, error) {
+	output, err := runInterruptibleCommand(ctx, true, "stash", "pop", stashRef)
+	if err != nil {
+		return output, fmt.Errorf("Failed to apply stash '%s'. Error: '%s'", stashRef, err.Error())
 	}
 
 	return output, nil
 }
 
-// UpdateCurrentBranchName updates current branch name
-func UpdateCurrentBranchName(branchName string) ([]byte, error) {
-	cmd := exec.Command("git", "branch", "-m", branchName)
+// GetRemotes returns names of repository remotes
+func GetRemotes(ctx context.Context) ([]string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "remote")
 	output, err := cmd.Output()
 	if err != nil {
-		return output, fmt.Errorf(
-			fmt.Sprintf("Failed to update branch name to '%s'. Error: '%s'", branchName, err.Error()),
-		)
+		return nil, fmt.Errorf("Failed to list git remotes. Error: '%s'", err.Error())
+	}
+
+	return strings.Fields(string(output)), nil
+}
+
+// Fetch downloads refs from remote, all remote branches are fetched when refspecs are not specified
+func Fetch(ctx context.Context, remote string, refspecs ...string) ([]byte, error) {
+	output, err := runInterruptibleCommand(ctx, true, append([]string{"fetch", remote}, refspecs...)...)
+	if err != nil {
+		return output, fmt.Errorf("Failed to fetch from remote '%s'. Error: '%s'", remote, err.Error())
 	}
 
 	return output, nil
+}
 
+// FindLatestRef returns short name of local or remote-tracking branch matching glob pattern
+// with the highest version, e.g. 'origin/release/1.10' for 'origin/release/*'
+func FindLatestRef(ctx context.Context, pattern string) (string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx,
+		"git", "for-each-ref", "--sort=-version:refname", "--format=%(refname:short)", "--count=1",
+		"refs/heads/"+pattern, "refs/remotes/"+pattern,
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Failed to find branch matching '%s'. Error: '%s'", pattern, err.Error())
+	}
+
+	return strings.TrimSpace(string(output)), nil
 }
 
-// GetCurrentBranchName returns current git branch
-func GetCurrentBranchName() (string, error) {
-	cmd := exec.Command("git", "branch", "--show-current")
+// ListBranches returns short names of local or remote-tracking branches
+func ListBranches(ctx context.Context, remote bool) ([]string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	refsPrefix := "refs/heads"
+	if remote {
+		refsPrefix = "refs/remotes"
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(refname:short) %(symref)", refsPrefix)
 	output, err := cmd.Output()
 	if err != nil {
-		return string(output), fmt.Errorf(
-			fmt.Sprintf("Failed get current git branch with. Error: '%s'", err.Error()),
+		return nil, fmt.Errorf("Failed to list git branches. Error: '%s'", err.Error())
+	}
+
+	var branches []string
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 1 {
+			continue
+		}
+		branches = append(branches, fields[0])
+	}
+
+	return branches, nil
+}
+
+// ListMergedBranches returns short names of local or remote-tracking branches merged into base branch
+func ListMergedBranches(ctx context.Context, baseBranch string, remote bool) ([]string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	args := []string{"branch", "--format=%(refname:short)", "--merged", baseBranch}
+	if remote {
+		args = append(args, "--remotes")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list branches merged into '%s'. Error: '%s'", baseBranch, err.Error())
+	}
+
+	return strings.Fields(string(output)), nil
+}
+
+// GetRemoteHeadBranch returns default branch of the remote, e.g. 'origin/main'
+func GetRemoteHeadBranch(ctx context.Context, remote string) (string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "symbolic-ref", "--short", fmt.Sprintf("refs/remotes/%s/HEAD", remote))
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Failed to get default branch of remote '%s'. Error: '%s'", remote, err.Error())
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DeleteBranch deletes local branch
+func DeleteBranch(ctx context.Context, branchName string) ([]byte, error) {
+	output, err := runInterruptibleCommand(ctx, true, "branch", "-D", branchName)
+	if err != nil {
+		return output, fmt.Errorf("Failed to delete branch '%s'. Error: '%s'", branchName, err.Error())
+	}
+
+	return output, nil
+}
+
+// DeleteRemoteBranch deletes branch on remote
+func DeleteRemoteBranch(ctx context.Context, remote string, branchName string) ([]byte, error) {
+	output, err := runInterruptibleCommand(ctx, true, "push", remote, "--delete", branchName)
+	if err != nil {
+		return output, fmt.Errorf("Failed to delete branch '%s' on remote '%s'. Error: '%s'", branchName, remote, err.Error())
+	}
+
+	return output, nil
+}
+
+// PushBranch pushes local branch to the branch with the same name on remote and sets it as upstream
+func PushBranch(ctx context.Context, remote string, branchName string) ([]byte, error) {
+	output, err := runInterruptibleCommand(ctx, true, "push", "--set-upstream", remote, branchName)
+	if err != nil {
+		return output, fmt.Errorf("Failed to push branch '%s' to remote '%s'. Error: '%s'", branchName, remote, err.Error())
+	}
+
+	return output, nil
+}
+
+// ListBranchesInfo returns local branches with their upstreams and last commit dates
+func ListBranchesInfo(ctx context.Context) ([]BranchInfo, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx,
+		"git", "for-each-ref", "--format=%(refname:short)%09%(upstream:short)%09%(committerdate:unix)", "refs/heads",
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list git branches. Error: '%s'", err.Error())
+	}
+
+	var branches []BranchInfo
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+
+		commitTimestamp, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse last commit date of branch '%s'. Error: '%s'", fields[0], err.Error())
+		}
+
+		branches = append(branches, BranchInfo{
+			Name:           fields[0],
+			Upstream:       fields[1],
+			LastCommitDate: time.Unix(commitTimestamp, 0),
+		})
+	}
+
+	return branches, nil
+}
+
+// CountAheadBehind returns numbers of commits that branch has and does not have comparing to base branch
+func CountAheadBehind(ctx context.Context, branchName string, baseBranch string) (int, int, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--left-right", "--count", branchName+"..."+baseBranch)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf(
+			"Failed to compare branch '%s' with '%s'. Error: '%s'", branchName, baseBranch, err.Error(),
 		)
 	}
 
-	return strings.TrimSuffix(string(output), "\n"), nil
+	counts := strings.Fields(string(output))
+	if len(counts) != 2 {
+		return 0, 0, fmt.Errorf("Unexpected output of branches comparison: '%s'", string(output))
+	}
+
+	ahead, err := strconv.Atoi(counts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := strconv.Atoi(counts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return ahead, behind, nil
+}
+
+type commandTimeoutKey struct{}
+
+// WithCommandTimeout returns context that limits duration of every read-only git command run with it.
+// Commands changing the repository are not limited, see runInterruptibleCommand.
+func WithCommandTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, commandTimeoutKey{}, timeout)
+}
+
+// withCommandTimeout limits duration of read-only git command by timeout set with WithCommandTimeout
+func withCommandTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout, _ := ctx.Value(commandTimeoutKey{}).(time.Duration)
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// commandInterruptDelay is a time given to interrupted git command to exit before it is killed
+const commandInterruptDelay = 10 * time.Second
+
+// runInterruptibleCommand runs git command changing the repository without a deadline. Cancellation of ctx
+// interrupts git with SIGINT like Ctrl-C does, so it can remove lock files and leave the repository consistent,
+// and the command is killed only when it does not exit within commandInterruptDelay.
+// Stderr is included into the output when combinedOutput is set and discarded otherwise.
+func runInterruptibleCommand(ctx context.Context, combinedOutput bool, args ...string) ([]byte, error) {
+	var output bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &output
+	if combinedOutput {
+		cmd.Stderr = &output
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return output.Bytes(), err
+	case <-ctx.Done():
+	}
+
+	cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-done:
+	case <-time.After(commandInterruptDelay):
+		cmd.Process.Kill()
+		<-done
+	}
+	return output.Bytes(), ctx.Err()
 }
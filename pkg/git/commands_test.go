@@ -0,0 +1,134 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFindBranchBySubstring(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.CreateBranch("PC-1/local_branch")
+	repo.CreateRemoteBranch("PC-2/remote_branch")
+
+	tests := []struct {
+		substring string
+		want      string
+	}{
+		{"PC-1/", "PC-1/local_branch"},
+		{"PC-2/", "PC-2/remote_branch"},
+		{"PC-3/", ""},
+	}
+
+	for _, tt := range tests {
+		branchName, err := FindBranchBySubstring(context.Background(), NewExecRepository(), tt.substring)
+		if err != nil {
+			t.Errorf("FindBranchBySubstring for '%s' returned error %+v", tt.substring, err.Error())
+		}
+		if branchName != tt.want {
+			t.Errorf("FindBranchBySubstring for '%s' returned %+v, want %+v", tt.substring, branchName, tt.want)
+		}
+	}
+}
+
+func TestCheckoutBranch(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.CreateBranch("PC-1/local_branch")
+	repo.CreateRemoteBranch("PC-2/remote_branch")
+
+	_, err := CheckoutBranch(context.Background(), "PC-1/local_branch")
+	if err != nil {
+		t.Errorf("CheckoutBranch of local branch returned error %+v", err.Error())
+	}
+	if repo.CurrentBranch() != "PC-1/local_branch" {
+		t.Errorf("CheckoutBranch of local branch switched to %+v", repo.CurrentBranch())
+	}
+
+	_, err = CheckoutBranch(context.Background(), "PC-2/remote_branch")
+	if err != nil {
+		t.Errorf("CheckoutBranch of remote branch returned error %+v", err.Error())
+	}
+	if repo.CurrentBranch() != "PC-2/remote_branch" {
+		t.Errorf("CheckoutBranch of remote branch switched to %+v", repo.CurrentBranch())
+	}
+	if upstream := repo.Git("rev-parse", "--abbrev-ref", "@{upstream}"); upstream != "origin/PC-2/remote_branch" {
+		t.Errorf("CheckoutBranch of remote branch set upstream %+v, want origin/PC-2/remote_branch", upstream)
+	}
+
+	_, err = CheckoutBranch(context.Background(), "PC-3/missing_branch")
+	if err == nil {
+		t.Errorf("CheckoutBranch of missing branch did not return error")
+	}
+	if repo.CurrentBranch() != "PC-2/remote_branch" {
+		t.Errorf("CheckoutBranch of missing branch switched to %+v", repo.CurrentBranch())
+	}
+}
+
+func TestCheckoutNewBranch(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.Commit("Local commit")
+
+	_, err := CheckoutNewBranch(context.Background(), "PC-1/from_head", "")
+	if err != nil {
+		t.Errorf("CheckoutNewBranch from HEAD returned error %+v", err.Error())
+	}
+	if repo.CurrentBranch() != "PC-1/from_head" || repo.Git("log", "-1", "--format=%s") != "Local commit" {
+		t.Errorf("CheckoutNewBranch from HEAD switched to %+v", repo.CurrentBranch())
+	}
+
+	_, err = CheckoutNewBranch(context.Background(), "PC-2/from_origin", "origin/main")
+	if err != nil {
+		t.Errorf("CheckoutNewBranch from origin/main returned error %+v", err.Error())
+	}
+	if repo.Git("rev-parse", "HEAD") != repo.Git("rev-parse", "origin/main") {
+		t.Errorf("CheckoutNewBranch from origin/main did not start branch at origin/main")
+	}
+	if upstream := repo.Git("config", "--default", "", "branch.PC-2/from_origin.merge"); upstream != "" {
+		t.Errorf("CheckoutNewBranch from origin/main set upstream %+v, want none", upstream)
+	}
+
+	_, err = CheckoutNewBranch(context.Background(), "PC-1/from_head", "")
+	if err == nil {
+		t.Errorf("CheckoutNewBranch of existing branch did not return error")
+	}
+}
+
+func TestUpdateCurrentBranchName(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.Git("checkout", "--quiet", "-b", "PC-1/old_name")
+
+	_, err := UpdateCurrentBranchName(context.Background(), "PC-1/new_name")
+	if err != nil {
+		t.Errorf("UpdateCurrentBranchName returned error %+v", err.Error())
+	}
+	if repo.CurrentBranch() != "PC-1/new_name" || repo.HasBranch("PC-1/old_name") {
+		t.Errorf("UpdateCurrentBranchName renamed branch to %+v", repo.CurrentBranch())
+	}
+
+	currentBranchName, err := GetCurrentBranchName(context.Background())
+	if err != nil {
+		t.Errorf("GetCurrentBranchName returned error %+v", err.Error())
+	}
+	if currentBranchName != "PC-1/new_name" {
+		t.Errorf("GetCurrentBranchName returned %+v, want PC-1/new_name", currentBranchName)
+	}
+}
+
+func TestCheckoutBranch_IgnoresCommandTimeout(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.CreateBranch("PC-1/local_branch")
+
+	ctx := WithCommandTimeout(context.Background(), time.Nanosecond)
+	if _, err := CheckoutBranch(ctx, "PC-1/local_branch"); err != nil {
+		t.Errorf("CheckoutBranch with command timeout returned error %+v", err.Error())
+	}
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CheckoutBranch(cancelledCtx, "master"); err == nil {
+		t.Errorf("CheckoutBranch with cancelled context did not return error")
+	}
+	if repo.CurrentBranch() != "PC-1/local_branch" {
+		t.Errorf("CheckoutBranch with cancelled context switched to %+v", repo.CurrentBranch())
+	}
+}
@@ -0,0 +1,100 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+// IssueKeysPosition is a type for enum values of issue keys position in commit messages
+type IssueKeysPosition string
+
+// IssueKeysPrefix is a holder of issue keys position name
+const (
+	IssueKeysPrefix IssueKeysPosition = "prefix"
+	IssueKeysSuffix IssueKeysPosition = "suffix"
+)
+
+const commitMessageCommentPrefix = "#"
+
+var fixupCommitMessagePrefixes = []string{"fixup!", "squash!", "amend!"}
+
+// CommitMessageContainsIssueKey checks if commit message already mentions a Jira issue of the project
+func (namer BranchNamer) CommitMessageContainsIssueKey(message string) bool {
+	reg := regexp.MustCompile(`(^|[^A-Za-z0-9])` + regexp.QuoteMeta(namer.IssueKeyPrefix()) + `[0-9]+`)
+	for _, line := range getCommitMessageBodyLines(message) {
+		if reg.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFixupCommitMessage checks if commit message was generated by git commit --fixup or --squash
+func IsFixupCommitMessage(message string) bool {
+	for _, line := range getCommitMessageBodyLines(message) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		for _, prefix := range fixupCommitMessagePrefixes {
+			if strings.HasPrefix(line, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// AddIssueKeysToCommitMessage adds issue keys to the subject line or to the end of commit message body.
+// Comment lines that git appends to the message are kept at the end.
+func AddIssueKeysToCommitMessage(message string, issueKeys []string, position IssueKeysPosition) string {
+	lines := strings.Split(message, "\n")
+	bodyLinesCount := len(getCommitMessageBodyLines(message))
+	bodyLines := append([]string{}, lines[:bodyLinesCount]...)
+	commentLines := lines[bodyLinesCount:]
+	joinedIssueKeys := strings.Join(issueKeys, " ")
+
+	if position == IssueKeysSuffix {
+		for len(bodyLines) > 0 && strings.TrimSpace(bodyLines[len(bodyLines)-1]) == "" {
+			bodyLines = bodyLines[:len(bodyLines)-1]
+		}
+		if len(bodyLines) > 0 {
+			bodyLines = append(bodyLines, "")
+		}
+		bodyLines = append(bodyLines, joinedIssueKeys)
+		if len(commentLines) == 0 && strings.HasSuffix(message, "\n") {
+			bodyLines = append(bodyLines, "")
+		}
+	} else {
+		subjectIndex := -1
+		for i, line := range bodyLines {
+			if strings.TrimSpace(line) != "" {
+				subjectIndex = i
+				break
+			}
+		}
+
+		if subjectIndex == -1 {
+			bodyLines = []string{joinedIssueKeys + " "}
+		} else {
+			bodyLines[subjectIndex] = joinedIssueKeys + " " + bodyLines[subjectIndex]
+		}
+	}
+
+	if len(commentLines) > 0 && strings.TrimSpace(bodyLines[len(bodyLines)-1]) != "" {
+		bodyLines = append(bodyLines, "")
+	}
+
+	return strings.Join(append(bodyLines, commentLines...), "\n")
+}
+
+// getCommitMessageBodyLines returns message lines preceding comments added by git
+func getCommitMessageBodyLines(message string) []string {
+	lines := strings.Split(message, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, commitMessageCommentPrefix) {
+			return lines[:i]
+		}
+	}
+	return lines
+}
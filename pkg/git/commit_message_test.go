@@ -0,0 +1,73 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestCommitMessageContainsIssueKey(t *testing.T) {
+	testCases := map[string]bool{
+		"PC-123 fix login":                      true,
+		"Fix login\n\nRelated to PC-12":         true,
+		"Fix login for APC-12":                  false,
+		"Fix login\n# PC-123/branch_name\n":     false,
+		"Fix login for PC- users":               false,
+		"\n# Please enter the commit message\n": false,
+	}
+
+	for message, expected := range testCases {
+		if result := testBranchNamer.CommitMessageContainsIssueKey(message); result != expected {
+			t.Errorf("CommitMessageContainsIssueKey(%q) returned %+v, want %+v", message, result, expected)
+		}
+	}
+}
+
+func TestIsFixupCommitMessage(t *testing.T) {
+	testCases := map[string]bool{
+		"fixup! PC-123 fix login":    true,
+		"\nsquash! fix login\n":      true,
+		"Fix login\n\nfixup! is bad": false,
+		"# fixup!\n":                 false,
+	}
+
+	for message, expected := range testCases {
+		if result := IsFixupCommitMessage(message); result != expected {
+			t.Errorf("IsFixupCommitMessage(%q) returned %+v, want %+v", message, result, expected)
+		}
+	}
+}
+
+func TestAddIssueKeysToCommitMessage_Prefix(t *testing.T) {
+	message := AddIssueKeysToCommitMessage("Fix login\n\nDetails\n", []string{"PC-1", "PC-2"}, IssueKeysPrefix)
+
+	expectedMessage := "PC-1 PC-2 Fix login\n\nDetails\n"
+	if message != expectedMessage {
+		t.Errorf("AddIssueKeysToCommitMessage returned %q, want %q", message, expectedMessage)
+	}
+}
+
+func TestAddIssueKeysToCommitMessage_PrefixToEmptyMessage(t *testing.T) {
+	message := AddIssueKeysToCommitMessage("\n# Please enter the commit message\n#\n", []string{"PC-1"}, IssueKeysPrefix)
+
+	expectedMessage := "PC-1 \n\n# Please enter the commit message\n#\n"
+	if message != expectedMessage {
+		t.Errorf("AddIssueKeysToCommitMessage returned %q, want %q", message, expectedMessage)
+	}
+}
+
+func TestAddIssueKeysToCommitMessage_Suffix(t *testing.T) {
+	message := AddIssueKeysToCommitMessage("Fix login\n\n# Please enter the commit message\n", []string{"PC-1"}, IssueKeysSuffix)
+
+	expectedMessage := "Fix login\n\nPC-1\n\n# Please enter the commit message\n"
+	if message != expectedMessage {
+		t.Errorf("AddIssueKeysToCommitMessage returned %q, want %q", message, expectedMessage)
+	}
+}
+
+func TestAddIssueKeysToCommitMessage_SuffixWithoutComments(t *testing.T) {
+	message := AddIssueKeysToCommitMessage("Fix login\n", []string{"PC-1"}, IssueKeysSuffix)
+
+	expectedMessage := "Fix login\n\nPC-1\n"
+	if message != expectedMessage {
+		t.Errorf("AddIssueKeysToCommitMessage returned %q, want %q", message, expectedMessage)
+	}
+}
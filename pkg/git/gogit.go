@@ -0,0 +1,532 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// goGitRepository is implemented in pure Go and does not require git binary
+type goGitRepository struct {
+	directory string
+	once      sync.Once
+	repo      *gogit.Repository
+	err       error
+}
+
+// OpenGoGitRepository returns pure Go repository containing the directory.
+// The repository is opened on the first use, so errors are returned by its methods.
+func OpenGoGitRepository(directory string) Repository {
+	return &goGitRepository{directory: directory}
+}
+
+// NewGoGitRepository returns Repository backed by already opened go-git repository,
+// e.g. in-memory one created with go-git Init
+func NewGoGitRepository(repo *gogit.Repository) Repository {
+	goGitRepo := &goGitRepository{repo: repo}
+	goGitRepo.once.Do(func() {})
+	return goGitRepo
+}
+
+func (r *goGitRepository) open(ctx context.Context) (*gogit.Repository, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.once.Do(func() {
+		r.repo, r.err = gogit.PlainOpenWithOptions(r.directory, &gogit.PlainOpenOptions{DetectDotGit: true})
+		if r.err != nil {
+			r.err = fmt.Errorf("Failed to open git repository '%s'. Error: '%s'", r.directory, r.err.Error())
+		}
+	})
+
+	return r.repo, r.err
+}
+
+func (r *goGitRepository) CurrentBranch(ctx context.Context) (string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return "", fmt.Errorf("Failed get current git branch with. Error: '%s'", err.Error())
+	}
+	if head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		return "", nil
+	}
+
+	return head.Target().Short(), nil
+}
+
+func (r *goGitRepository) ListBranches(ctx context.Context, remote bool) ([]string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list git branches. Error: '%s'", err.Error())
+	}
+
+	var branches []string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		if (remote && ref.Name().IsRemote()) || (!remote && ref.Name().IsBranch()) {
+			branches = append(branches, ref.Name().Short())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list git branches. Error: '%s'", err.Error())
+	}
+	sort.Strings(branches)
+
+	return branches, nil
+}
+
+func (r *goGitRepository) Checkout(ctx context.Context, branchName string) error {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = r.checkout(repo, branchName)
+	if err != nil {
+		return fmt.Errorf("Failed to switch to branch '%s'. Error: '%s'", branchName, err.Error())
+	}
+	return nil
+}
+
+func (r *goGitRepository) checkout(repo *gogit.Repository, branchName string) error {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	localRefName := plumbing.NewBranchReferenceName(branchName)
+	localRef, err := repo.Reference(localRefName, false)
+	if err == nil {
+		return checkoutKeepingLocalChanges(repo, worktree, gogit.CheckoutOptions{Branch: localRefName}, localRef.Hash())
+	}
+	if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return err
+	}
+
+	remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", branchName), true)
+	if err != nil {
+		return fmt.Errorf("pathspec '%s' did not match any branch", branchName)
+	}
+
+	options := gogit.CheckoutOptions{Hash: remoteRef.Hash(), Branch: localRefName, Create: true}
+	err = checkoutKeepingLocalChanges(repo, worktree, options, remoteRef.Hash())
+	if err != nil {
+		return err
+	}
+
+	return repo.CreateBranch(&gitconfig.Branch{Name: branchName, Remote: "origin", Merge: localRefName})
+}
+
+func (r *goGitRepository) CreateBranch(ctx context.Context, branchName string, startPoint string) error {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = r.createBranch(repo, branchName, startPoint)
+	if err != nil {
+		return fmt.Errorf("Failed to create a new branch '%s'. Error: '%s'", branchName, err.Error())
+	}
+	return nil
+}
+
+func (r *goGitRepository) createBranch(repo *gogit.Repository, branchName string, startPoint string) error {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	if startPoint == "" {
+		startPoint = string(plumbing.HEAD)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(startPoint))
+	if err != nil {
+		return fmt.Errorf("invalid start point '%s': %s", startPoint, err.Error())
+	}
+
+	options := gogit.CheckoutOptions{Hash: *hash, Branch: plumbing.NewBranchReferenceName(branchName), Create: true}
+	return checkoutKeepingLocalChanges(repo, worktree, options, *hash)
+}
+
+// checkoutKeepingLocalChanges switches branches like git checkout does: files that differ between the current
+// and the target commits are updated, local changes and untracked files are kept. The checkout fails before
+// switching when files with local changes differ between the commits. go-git Checkout only moves HEAD here,
+// because it refuses to switch with local changes and removes untracked files.
+func checkoutKeepingLocalChanges(
+	repo *gogit.Repository, worktree *gogit.Worktree, options gogit.CheckoutOptions, targetHash plumbing.Hash,
+) error {
+	targetCommit, err := repo.CommitObject(targetHash)
+	if err != nil {
+		return err
+	}
+	targetTree, err := targetCommit.Tree()
+	if err != nil {
+		return err
+	}
+
+	var headTree *object.Tree
+	head, err := repo.Head()
+	if err == nil {
+		headCommit, err := repo.CommitObject(head.Hash())
+		if err != nil {
+			return err
+		}
+		if headTree, err = headCommit.Tree(); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return err
+	}
+
+	changes, err := object.DiffTree(headTree, targetTree)
+	if err != nil {
+		return err
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return err
+	}
+	var overwrittenFiles []string
+	for _, change := range changes {
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			fileStatus, ok := status[name]
+			if name != "" && ok && (fileStatus.Staging != gogit.Unmodified || fileStatus.Worktree != gogit.Unmodified) &&
+				findElementInArray(overwrittenFiles, name) == -1 {
+				overwrittenFiles = append(overwrittenFiles, name)
+			}
+		}
+	}
+	if len(overwrittenFiles) > 0 {
+		sort.Strings(overwrittenFiles)
+		return fmt.Errorf(
+			"local changes of files would be overwritten by checkout, commit or stash them: %s",
+			strings.Join(overwrittenFiles, ", "),
+		)
+	}
+
+	options.Keep = true
+	if err := worktree.Checkout(&options); err != nil {
+		return err
+	}
+
+	return updateWorktreeFiles(repo, worktree, changes)
+}
+
+// updateWorktreeFiles writes changed files of the target commit to the worktree and the index,
+// deleted files are removed first, so that files can be replaced with directories
+func updateWorktreeFiles(repo *gogit.Repository, worktree *gogit.Worktree, changes object.Changes) error {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if change.From.Name != "" && change.From.Name != change.To.Name {
+			if err := removeWorktreeFile(worktree.Filesystem, change.From.Name); err != nil {
+				return err
+			}
+			idx.Remove(change.From.Name)
+		}
+	}
+
+	for _, change := range changes {
+		if change.To.Name == "" {
+			continue
+		}
+
+		entry := change.To.TreeEntry
+		if err := writeWorktreeFile(repo, worktree.Filesystem, change.To.Name, entry); err != nil {
+			return err
+		}
+
+		indexEntry, err := idx.Entry(change.To.Name)
+		if err != nil {
+			indexEntry = idx.Add(change.To.Name)
+		}
+		indexEntry.Hash = entry.Hash
+		indexEntry.Mode = entry.Mode
+		indexEntry.Size = 0
+		indexEntry.ModifiedAt = time.Time{}
+		if entry.Mode != filemode.Submodule {
+			info, err := worktree.Filesystem.Lstat(change.To.Name)
+			if err != nil {
+				return err
+			}
+			indexEntry.Size = uint32(info.Size())
+			indexEntry.ModifiedAt = info.ModTime()
+		}
+	}
+
+	return repo.Storer.SetIndex(idx)
+}
+
+func writeWorktreeFile(repo *gogit.Repository, filesystem billy.Filesystem, name string, entry object.TreeEntry) error {
+	if err := filesystem.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if entry.Mode == filemode.Submodule {
+		return filesystem.MkdirAll(name, 0755)
+	}
+	if err := filesystem.MkdirAll(path.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	blob, err := repo.BlobObject(entry.Hash)
+	if err != nil {
+		return err
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if entry.Mode == filemode.Symlink {
+		target, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		return filesystem.Symlink(string(target), name)
+	}
+
+	mode, err := entry.Mode.ToOSFileMode()
+	if err != nil {
+		return err
+	}
+	file, err := filesystem.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// removeWorktreeFile removes the file and its parent directories left empty
+func removeWorktreeFile(filesystem billy.Filesystem, name string) error {
+	if err := filesystem.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for directory := path.Dir(name); directory != "." && directory != "/"; directory = path.Dir(directory) {
+		if files, err := filesystem.ReadDir(directory); err != nil || len(files) > 0 || filesystem.Remove(directory) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func (r *goGitRepository) RenameCurrentBranch(ctx context.Context, branchName string) error {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = r.renameCurrentBranch(repo, branchName)
+	if err != nil {
+		return fmt.Errorf("Failed to update branch name to '%s'. Error: '%s'", branchName, err.Error())
+	}
+	return nil
+}
+
+func (r *goGitRepository) renameCurrentBranch(repo *gogit.Repository, branchName string) error {
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	if !head.Name().IsBranch() {
+		return errors.New("HEAD is detached")
+	}
+
+	oldRefName := head.Name()
+	newRefName := plumbing.NewBranchReferenceName(branchName)
+	if oldRefName == newRefName {
+		return nil
+	}
+	if _, err := repo.Reference(newRefName, false); err == nil {
+		return fmt.Errorf("a branch named '%s' already exists", branchName)
+	}
+
+	err = repo.Storer.SetReference(plumbing.NewHashReference(newRefName, head.Hash()))
+	if err != nil {
+		return err
+	}
+	err = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, newRefName))
+	if err != nil {
+		return err
+	}
+	err = repo.Storer.RemoveReference(oldRefName)
+	if err != nil {
+		return err
+	}
+
+	repoConfig, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	if branchConfig, ok := repoConfig.Branches[oldRefName.Short()]; ok {
+		delete(repoConfig.Branches, oldRefName.Short())
+		branchConfig.Name = branchName
+		repoConfig.Branches[branchName] = branchConfig
+		return repo.Storer.SetConfig(repoConfig)
+	}
+
+	return nil
+}
+
+func (r *goGitRepository) Remotes(ctx context.Context) ([]string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gitRemotes, err := repo.Remotes()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list git remotes. Error: '%s'", err.Error())
+	}
+
+	var remotes []string
+	for _, gitRemote := range gitRemotes {
+		remotes = append(remotes, gitRemote.Config().Name)
+	}
+	sort.Strings(remotes)
+
+	return remotes, nil
+}
+
+func (r *goGitRepository) BranchRemote(ctx context.Context, branchName string) (string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	repoConfig, err := repo.Config()
+	if err != nil {
+		return "", fmt.Errorf("Failed to get upstream remote of branch '%s'. Error: '%s'", branchName, err.Error())
+	}
+	if branchConfig, ok := repoConfig.Branches[branchName]; ok {
+		return branchConfig.Remote, nil
+	}
+
+	return "", nil
+}
+
+func (r *goGitRepository) RemoteURL(ctx context.Context, remote string) (string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	gitRemote, err := repo.Remote(remote)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get URL of git remote '%s'. Error: '%s'", remote, err.Error())
+	}
+	if urls := gitRemote.Config().URLs; len(urls) > 0 {
+		return urls[0], nil
+	}
+
+	return "", fmt.Errorf("Git remote '%s' has no URL", remote)
+}
+
+func (r *goGitRepository) ChangedFiles(ctx context.Context) ([]string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get git working tree status. Error: '%s'", err.Error())
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get git working tree status. Error: '%s'", err.Error())
+	}
+
+	var files []string
+	for path, fileStatus := range status {
+		if fileStatus.Worktree == gogit.Untracked || (fileStatus.Worktree == gogit.Unmodified && fileStatus.Staging == gogit.Unmodified) {
+			continue
+		}
+		files = append(files, path)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func (r *goGitRepository) Log(ctx context.Context, revision string, limit int) ([]Commit, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if revision == "" {
+		revision = string(plumbing.HEAD)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get git log of '%s'. Error: '%s'", revision, err.Error())
+	}
+
+	commitIter, err := repo.Log(&gogit.LogOptions{From: *hash, Order: gogit.LogOrderCommitterTime})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get git log of '%s'. Error: '%s'", revision, err.Error())
+	}
+	defer commitIter.Close()
+
+	var commits []Commit
+	err = commitIter.ForEach(func(commit *object.Commit) error {
+		if len(commits) >= limit {
+			return storer.ErrStop
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		commits = append(commits, Commit{
+			Hash:    commit.Hash.String(),
+			Author:  commit.Author.Name,
+			Date:    commit.Author.When,
+			Subject: strings.SplitN(strings.TrimSpace(commit.Message), "\n", 2)[0],
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get git log of '%s'. Error: '%s'", revision, err.Error())
+	}
+
+	return commits, nil
+}
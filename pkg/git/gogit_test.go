@@ -0,0 +1,306 @@
+package git
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// memoryRepository is an in-memory go-git repository with a commit on main branch
+type memoryRepository struct {
+	t          *testing.T
+	Repo       *gogit.Repository
+	Filesystem billy.Filesystem
+}
+
+func newMemoryRepository(t *testing.T) *memoryRepository {
+	t.Helper()
+
+	filesystem := memfs.New()
+	repo, err := gogit.Init(memory.NewStorage(), filesystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{"git@github.com:macanton/got.git"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memoryRepo := &memoryRepository{t: t, Repo: repo, Filesystem: filesystem}
+	memoryRepo.WriteFile("README.md", "got")
+	memoryRepo.Commit("Initial commit")
+
+	return memoryRepo
+}
+
+// WriteFile replaces content of the file in the working tree
+func (repo *memoryRepository) WriteFile(path string, content string) {
+	repo.t.Helper()
+
+	file, err := repo.Filesystem.Create(path)
+	if err != nil {
+		repo.t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.Write([]byte(content)); err != nil {
+		repo.t.Fatal(err)
+	}
+}
+
+// Commit commits all changes of tracked and new files
+func (repo *memoryRepository) Commit(message string) plumbing.Hash {
+	repo.t.Helper()
+
+	worktree, err := repo.Repo.Worktree()
+	if err != nil {
+		repo.t.Fatal(err)
+	}
+	if err := worktree.AddGlob("."); err != nil {
+		repo.t.Fatal(err)
+	}
+	hash, err := worktree.Commit(message, &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		repo.t.Fatal(err)
+	}
+
+	return hash
+}
+
+func TestGoGitRepositoryBranches(t *testing.T) {
+	ctx := context.Background()
+	memoryRepo := newMemoryRepository(t)
+	repository := NewGoGitRepository(memoryRepo.Repo)
+
+	currentBranchName, err := repository.CurrentBranch(ctx)
+	if err != nil || currentBranchName != "main" {
+		t.Errorf("CurrentBranch returned %+v, %+v, want main", currentBranchName, err)
+	}
+
+	if err := repository.CreateBranch(ctx, "PC-1/old_name", ""); err != nil {
+		t.Errorf("CreateBranch returned error %+v", err.Error())
+	}
+	if err := repository.CreateBranch(ctx, "PC-1/old_name", "main"); err == nil {
+		t.Errorf("CreateBranch of existing branch did not return error")
+	}
+	if err := repository.RenameCurrentBranch(ctx, "PC-1/new_name"); err != nil {
+		t.Errorf("RenameCurrentBranch returned error %+v", err.Error())
+	}
+
+	currentBranchName, _ = repository.CurrentBranch(ctx)
+	if currentBranchName != "PC-1/new_name" {
+		t.Errorf("CurrentBranch after rename returned %+v, want PC-1/new_name", currentBranchName)
+	}
+	branches, err := repository.ListBranches(ctx, false)
+	if err != nil || !reflect.DeepEqual(branches, []string{"PC-1/new_name", "main"}) {
+		t.Errorf("ListBranches returned %+v, %+v", branches, err)
+	}
+
+	remoteCommit := memoryRepo.Commit("Remote commit")
+	err = memoryRepo.Repo.Storer.SetReference(
+		plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", "PC-2/remote_branch"), remoteCommit),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repository.Checkout(ctx, "main"); err != nil {
+		t.Errorf("Checkout of local branch returned error %+v", err.Error())
+	}
+
+	branchName, err := FindBranchBySubstring(ctx, repository, "PC-2/")
+	if err != nil || branchName != "PC-2/remote_branch" {
+		t.Errorf("FindBranchBySubstring returned %+v, %+v, want PC-2/remote_branch", branchName, err)
+	}
+	if err := repository.Checkout(ctx, branchName); err != nil {
+		t.Errorf("Checkout of remote branch returned error %+v", err.Error())
+	}
+	head, _ := memoryRepo.Repo.Head()
+	if head.Name().Short() != "PC-2/remote_branch" || head.Hash() != remoteCommit {
+		t.Errorf("Checkout of remote branch switched to %+v", head)
+	}
+	repoConfig, _ := memoryRepo.Repo.Config()
+	if branchConfig := repoConfig.Branches["PC-2/remote_branch"]; branchConfig == nil || branchConfig.Remote != "origin" {
+		t.Errorf("Checkout of remote branch did not set upstream, got %+v", branchConfig)
+	}
+
+	if err := repository.Checkout(ctx, "PC-3/missing_branch"); err == nil {
+		t.Errorf("Checkout of missing branch did not return error")
+	}
+}
+
+func TestGoGitRepositoryChangedFiles(t *testing.T) {
+	ctx := context.Background()
+	memoryRepo := newMemoryRepository(t)
+	repository := NewGoGitRepository(memoryRepo.Repo)
+
+	changedFiles, err := repository.ChangedFiles(ctx)
+	if err != nil || len(changedFiles) != 0 {
+		t.Errorf("ChangedFiles of clean working tree returned %+v, %+v", changedFiles, err)
+	}
+
+	memoryRepo.WriteFile("README.md", "got got")
+	memoryRepo.WriteFile("untracked.txt", "untracked")
+
+	changedFiles, err = repository.ChangedFiles(ctx)
+	if err != nil || !reflect.DeepEqual(changedFiles, []string{"README.md"}) {
+		t.Errorf("ChangedFiles returned %+v, %+v, want [README.md]", changedFiles, err)
+	}
+}
+
+func TestGoGitRepositoryLogAndRemote(t *testing.T) {
+	ctx := context.Background()
+	memoryRepo := newMemoryRepository(t)
+	memoryRepo.WriteFile("README.md", "got got")
+	hash := memoryRepo.Commit("Second commit\n\nWith body")
+	repository := NewGoGitRepository(memoryRepo.Repo)
+
+	commits, err := repository.Log(ctx, "", 1)
+	if err != nil || len(commits) != 1 {
+		t.Fatalf("Log returned %+v, %+v, want one commit", commits, err)
+	}
+	if commits[0].Hash != hash.String() || commits[0].Subject != "Second commit" || commits[0].Author != "Test" {
+		t.Errorf("Log returned %+v", commits[0])
+	}
+
+	repositoryURL, err := GetRepositoryURL(ctx, repository, "")
+	if err != nil || repositoryURL.Path() != "macanton/got" {
+		t.Errorf("GetRepositoryURL returned %+v, %+v, want macanton/got", repositoryURL, err)
+	}
+	if _, err := repository.RemoteURL(ctx, "upstream"); err == nil {
+		t.Errorf("RemoteURL of missing remote did not return error")
+	}
+}
+
+func TestRepositoryBackendsOnDisk(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	repo.Commit("Local commit")
+	repo.CreateRemoteBranch("PC-2/remote_branch")
+
+	backends := map[Backend]Repository{
+		BackendExec:  NewExecRepository(),
+		BackendGoGit: OpenGoGitRepository("."),
+	}
+	for backend, repository := range backends {
+		currentBranchName, err := repository.CurrentBranch(ctx)
+		if err != nil || currentBranchName != "main" {
+			t.Errorf("%s CurrentBranch returned %+v, %+v, want main", backend, currentBranchName, err)
+		}
+
+		remoteBranches, err := repository.ListBranches(ctx, true)
+		if err != nil || !reflect.DeepEqual(remoteBranches, []string{"origin/PC-2/remote_branch", "origin/main"}) {
+			t.Errorf("%s ListBranches returned %+v, %+v", backend, remoteBranches, err)
+		}
+
+		remoteURL, err := repository.RemoteURL(ctx, "origin")
+		if err != nil || remoteURL != repo.Remote {
+			t.Errorf("%s RemoteURL returned %+v, %+v, want %+v", backend, remoteURL, err, repo.Remote)
+		}
+
+		commits, err := repository.Log(ctx, "main", 5)
+		if err != nil || len(commits) != 2 || commits[0].Subject != "Local commit" || commits[0].Author != "Test" {
+			t.Errorf("%s Log returned %+v, %+v", backend, commits, err)
+		}
+	}
+}
+
+func TestRepositoryBackendsCheckoutWithLocalChanges(t *testing.T) {
+	ctx := context.Background()
+	backends := map[Backend]func() Repository{
+		BackendExec:  NewExecRepository,
+		BackendGoGit: func() Repository { return OpenGoGitRepository(".") },
+	}
+	for backend, openRepository := range backends {
+		t.Run(string(backend), func(t *testing.T) {
+			repo := newTestRepository(t)
+			writeTestFiles(t, map[string]string{"local.txt": "local\n", "staged.txt": "staged\n", "branch.txt": "main\n"})
+			repo.Git("add", ".")
+			repo.Commit("Add files")
+			repo.Git("checkout", "--quiet", "-b", "PC-1/branch")
+			writeTestFiles(t, map[string]string{"branch.txt": "PC-1\n", "dir/new.txt": "new\n"})
+			repo.Git("add", ".")
+			repo.Commit("Change files")
+			repo.Git("checkout", "--quiet", "main")
+
+			writeTestFiles(t, map[string]string{"local.txt": "changed\n", "staged.txt": "changed\n", "untracked.txt": "untracked\n"})
+			repo.Git("add", "staged.txt")
+
+			repository := openRepository()
+			if err := repository.Checkout(ctx, "PC-1/branch"); err != nil {
+				t.Fatalf("Checkout with local changes returned error %+v", err)
+			}
+			// status output is trimmed, so unstaged change of the first file has no leading space
+			wantStatus := "M local.txt\nM  staged.txt\n?? untracked.txt"
+			status := repo.Git("status", "--short")
+			if repo.CurrentBranch() != "PC-1/branch" || status != wantStatus {
+				t.Errorf("Checkout with local changes switched to %s with status\n%s", repo.CurrentBranch(), status)
+			}
+			if content, _ := ioutil.ReadFile("dir/new.txt"); string(content) != "new\n" {
+				t.Errorf("Checkout with local changes left dir/new.txt with %q", content)
+			}
+
+			if err := repository.CreateBranch(ctx, "PC-2/branch", ""); err != nil {
+				t.Fatalf("CreateBranch with local changes returned error %+v", err)
+			}
+			if status := repo.Git("status", "--short"); repo.CurrentBranch() != "PC-2/branch" || status != wantStatus {
+				t.Errorf("CreateBranch with local changes switched to %s with status\n%s", repo.CurrentBranch(), status)
+			}
+
+			writeTestFiles(t, map[string]string{"branch.txt": "changed\n"})
+			if err := repository.Checkout(ctx, "main"); err == nil {
+				t.Errorf("Checkout overwriting local changes did not return error")
+			}
+			if content, _ := ioutil.ReadFile("branch.txt"); repo.CurrentBranch() != "PC-2/branch" || string(content) != "changed\n" {
+				t.Errorf("failed Checkout switched to %s and left branch.txt with %q", repo.CurrentBranch(), content)
+			}
+
+			writeTestFiles(t, map[string]string{"branch.txt": "PC-1\n"})
+			if err := repository.Checkout(ctx, "main"); err != nil {
+				t.Fatalf("Checkout back to main returned error %+v", err)
+			}
+			if _, err := os.Stat("dir"); !os.IsNotExist(err) || repo.Git("status", "--short") != wantStatus {
+				t.Errorf("Checkout back to main kept dir or changed status to\n%s", repo.Git("status", "--short"))
+			}
+		})
+	}
+}
+
+func writeTestFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOpenRepository(t *testing.T) {
+	for _, backend := range []Backend{"", BackendAuto, BackendExec, BackendGoGit} {
+		if _, err := OpenRepository(backend); err != nil {
+			t.Errorf("OpenRepository(%+v) returned error %+v", backend, err.Error())
+		}
+	}
+	if _, err := OpenRepository("svn"); err == nil {
+		t.Errorf("OpenRepository of unknown backend did not return error")
+	}
+}
@@ -0,0 +1,159 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DefaultRemote is a name of the remote used when the current branch has no upstream
+const DefaultRemote = "origin"
+
+// RepositoryURL is a struct for parts of a remote repository URL
+type RepositoryURL struct {
+	// Host is a server name without user and port, empty for local repositories
+	Host string
+	// Owner is a user, organisation or nested groups separated with slashes
+	Owner string
+	// Name is a repository name without .git suffix
+	Name string
+}
+
+// Path returns owner and name of the repository separated with a slash
+func (repositoryURL RepositoryURL) Path() string {
+	if repositoryURL.Owner == "" {
+		return repositoryURL.Name
+	}
+	return repositoryURL.Owner + "/" + repositoryURL.Name
+}
+
+// ExpandTemplate replaces {host}, {owner}, {repo} and {path} placeholders with parts of the URL
+func (repositoryURL RepositoryURL) ExpandTemplate(template string) string {
+	replacer := strings.NewReplacer(
+		"{host}", repositoryURL.Host,
+		"{owner}", repositoryURL.Owner,
+		"{repo}", repositoryURL.Name,
+		"{path}", repositoryURL.Path(),
+	)
+	return replacer.Replace(template)
+}
+
+// scpLikeURLRegexp matches URLs like 'git@github.com:owner/repo.git'
+var scpLikeURLRegexp = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.*)$`)
+
+// ParseRepositoryURL parses SSH, scp-like, HTTP(S), git, file URLs and local paths of repositories
+func ParseRepositoryURL(rawURL string) (RepositoryURL, error) {
+	rawURL = strings.TrimSpace(rawURL)
+
+	var host, repositoryPath string
+	local := false
+	if strings.Contains(rawURL, "://") {
+		parsedURL, err := url.Parse(rawURL)
+		if err != nil {
+			return RepositoryURL{}, fmt.Errorf("Invalid repository URL '%s': %s", rawURL, err.Error())
+		}
+		host = parsedURL.Hostname()
+		repositoryPath = parsedURL.Path
+		local = parsedURL.Scheme == "file"
+	} else if matches := scpLikeURLRegexp.FindStringSubmatch(rawURL); matches != nil && len(matches[1]) > 1 {
+		host = matches[1]
+		repositoryPath = matches[2]
+		// ports are not allowed in scp-like URLs, but 'git@host:2222/owner/repo.git' is a common mistake.
+		// Numeric owners like 'git@github.com:1234/repo.git' are kept, the port is followed by owner and name.
+		segments := strings.SplitN(strings.TrimPrefix(repositoryPath, "/"), "/", 2)
+		if len(segments) == 2 && isNumber(segments[0]) && strings.Contains(segments[1], "/") {
+			repositoryPath = segments[1]
+		}
+	} else {
+		repositoryPath = rawURL
+		local = true
+	}
+
+	repositoryPath = strings.Trim(strings.ReplaceAll(repositoryPath, "\\", "/"), "/")
+	repositoryPath = strings.TrimSuffix(strings.TrimSuffix(repositoryPath, "/.git"), ".git")
+	repositoryPath = strings.TrimRight(repositoryPath, "/")
+
+	owner, name := path.Split(repositoryPath)
+	owner = strings.Trim(owner, "/")
+	if local {
+		owner = path.Base(owner)
+		if owner == "." || owner == "/" {
+			owner = ""
+		}
+	}
+	if name == "" {
+		return RepositoryURL{}, fmt.Errorf("Repository URL '%s' does not contain repository name", rawURL)
+	}
+
+	return RepositoryURL{Host: host, Owner: owner, Name: name}, nil
+}
+
+// FindRemote returns configured remote, the remote of the current branch upstream,
+// origin or the only remote of the repository
+func FindRemote(ctx context.Context, repository Repository, configuredRemote string) (string, error) {
+	if configuredRemote != "" {
+		return configuredRemote, nil
+	}
+
+	currentBranchName, err := repository.CurrentBranch(ctx)
+	if err != nil {
+		return "", err
+	}
+	if currentBranchName != "" {
+		upstreamRemote, err := repository.BranchRemote(ctx, currentBranchName)
+		if err != nil {
+			return "", err
+		}
+		// '.' is used for upstreams that are local branches
+		if upstreamRemote != "" && upstreamRemote != "." {
+			return upstreamRemote, nil
+		}
+	}
+
+	remotes, err := repository.Remotes(ctx)
+	if err != nil {
+		return "", err
+	}
+	if findElementInArray(remotes, DefaultRemote) != -1 {
+		return DefaultRemote, nil
+	}
+	if len(remotes) == 1 {
+		return remotes[0], nil
+	}
+	if len(remotes) == 0 {
+		return "", errors.New("Repository has no git remotes")
+	}
+
+	return "", fmt.Errorf("Cannot choose git remote from '%s', set git.remote in config", strings.Join(remotes, "', '"))
+}
+
+// GetRepositoryURL returns parsed URL of the remote chosen with FindRemote
+func GetRepositoryURL(ctx context.Context, repository Repository, configuredRemote string) (RepositoryURL, error) {
+	remote, err := FindRemote(ctx, repository, configuredRemote)
+	if err != nil {
+		return RepositoryURL{}, err
+	}
+
+	remoteURL, err := repository.RemoteURL(ctx, remote)
+	if err != nil {
+		return RepositoryURL{}, err
+	}
+
+	return ParseRepositoryURL(remoteURL)
+}
+
+func isNumber(str string) bool {
+	if str == "" {
+		return false
+	}
+	for _, char := range str {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
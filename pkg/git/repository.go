@@ -0,0 +1,206 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Repository is a git repository of the current working tree
+type Repository interface {
+	// CurrentBranch returns name of the checked out branch, empty for detached HEAD
+	CurrentBranch(ctx context.Context) (string, error)
+	// ListBranches returns short names of local or remote-tracking branches
+	ListBranches(ctx context.Context, remote bool) ([]string, error)
+	// Checkout switches to local branch or creates local branch tracking remote branch of origin with the same name
+	Checkout(ctx context.Context, branchName string) error
+	// CreateBranch creates and checks out new branch from start point, empty start point means current HEAD
+	CreateBranch(ctx context.Context, branchName string, startPoint string) error
+	// RenameCurrentBranch renames the checked out branch
+	RenameCurrentBranch(ctx context.Context, branchName string) error
+	// Remotes returns names of repository remotes
+	Remotes(ctx context.Context) ([]string, error)
+	// BranchRemote returns remote of the local branch upstream, empty when the branch has no upstream
+	BranchRemote(ctx context.Context, branchName string) (string, error)
+	// RemoteURL returns fetch URL of the remote
+	RemoteURL(ctx context.Context, remote string) (string, error)
+	// ChangedFiles returns paths of tracked files with local changes
+	ChangedFiles(ctx context.Context) ([]string, error)
+	// Log returns up to limit commits reachable from revision, empty revision means HEAD
+	Log(ctx context.Context, revision string, limit int) ([]Commit, error)
+}
+
+// Backend is a type for enum values of git repository implementations
+type Backend string
+
+// BackendAuto is a holder of git backend name
+const (
+	BackendAuto  Backend = "auto"
+	BackendExec  Backend = "exec"
+	BackendGoGit Backend = "go-git"
+)
+
+// OpenRepository returns repository of the current directory implemented by the backend.
+// Auto backend runs git binary when it is installed and falls back to the pure Go implementation.
+func OpenRepository(backend Backend) (Repository, error) {
+	switch backend {
+	case BackendExec:
+		return NewExecRepository(), nil
+	case BackendGoGit:
+		return OpenGoGitRepository("."), nil
+	case "", BackendAuto:
+		if IsGitInstalled() {
+			return NewExecRepository(), nil
+		}
+		return OpenGoGitRepository("."), nil
+	default:
+		return nil, fmt.Errorf("Unknown git backend '%s', use auto, exec or go-git", backend)
+	}
+}
+
+// IsGitInstalled checks if git binary is installed. Repository is implemented by the go-git backend as well,
+// but worktrees, stashes, fetches, pushes, hooks and branch comparisons always run git binary.
+func IsGitInstalled() bool {
+	_, err := exec.LookPath("git")
+	return err == nil
+}
+
+// FindBranchBySubstring returns the first local branch containing substring or
+// the first remote-tracking branch containing it without the remote name
+func FindBranchBySubstring(ctx context.Context, repository Repository, substring string) (string, error) {
+	localBranches, err := repository.ListBranches(ctx, false)
+	if err != nil {
+		return "", err
+	}
+	for _, branchName := range localBranches {
+		if strings.Contains(branchName, substring) {
+			return branchName, nil
+		}
+	}
+
+	remoteBranches, err := repository.ListBranches(ctx, true)
+	if err != nil {
+		return "", err
+	}
+	for _, branchName := range remoteBranches {
+		separatorIndex := strings.Index(branchName, "/")
+		if separatorIndex == -1 {
+			continue
+		}
+		if localBranchName := branchName[separatorIndex+1:]; strings.Contains(localBranchName, substring) {
+			return localBranchName, nil
+		}
+	}
+
+	return "", nil
+}
+
+// execRepository runs git binary in the current directory
+type execRepository struct{}
+
+// NewExecRepository returns repository of the current directory that runs git binary
+func NewExecRepository() Repository {
+	return execRepository{}
+}
+
+func (execRepository) CurrentBranch(ctx context.Context) (string, error) {
+	return GetCurrentBranchName(ctx)
+}
+
+func (execRepository) ListBranches(ctx context.Context, remote bool) ([]string, error) {
+	return ListBranches(ctx, remote)
+}
+
+func (execRepository) Checkout(ctx context.Context, branchName string) error {
+	_, err := CheckoutBranch(ctx, branchName)
+	return err
+}
+
+func (execRepository) CreateBranch(ctx context.Context, branchName string, startPoint string) error {
+	_, err := CheckoutNewBranch(ctx, branchName, startPoint)
+	return err
+}
+
+func (execRepository) RenameCurrentBranch(ctx context.Context, branchName string) error {
+	_, err := UpdateCurrentBranchName(ctx, branchName)
+	return err
+}
+
+func (execRepository) Remotes(ctx context.Context) ([]string, error) {
+	return GetRemotes(ctx)
+}
+
+func (execRepository) BranchRemote(ctx context.Context, branchName string) (string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "config", "--get", "branch."+branchName+".remote")
+	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Failed to get upstream remote of branch '%s'. Error: '%s'", branchName, err.Error())
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (execRepository) RemoteURL(ctx context.Context, remote string) (string, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "remote", "get-url", remote)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Failed to get URL of git remote '%s'. Error: '%s'", remote, err.Error())
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (execRepository) ChangedFiles(ctx context.Context) ([]string, error) {
+	return GetChangedFiles(ctx)
+}
+
+func (execRepository) Log(ctx context.Context, revision string, limit int) ([]Commit, error) {
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
+	if revision == "" {
+		revision = "HEAD"
+	}
+	cmd := exec.CommandContext(
+		ctx, "git", "log", "--format=%H%x09%an%x09%at%x09%s", fmt.Sprintf("--max-count=%d", limit), revision, "--",
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get git log of '%s'. Error: '%s'", revision, err.Error())
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) != 4 {
+			continue
+		}
+
+		timestamp, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse commit date '%s': %s", fields[2], err.Error())
+		}
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Date:    time.Unix(timestamp, 0),
+			Subject: fields[3],
+		})
+	}
+
+	return commits, nil
+}
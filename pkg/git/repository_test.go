@@ -0,0 +1,125 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testRepository is a temporary git repository with a bare repository on disk as origin remote.
+// The current directory is switched to the repository until the test finishes, so tests using it
+// must not run in parallel.
+type testRepository struct {
+	t         *testing.T
+	Directory string
+	Remote    string
+}
+
+// newTestRepository creates repository with initial commit on main branch pushed to origin
+func newTestRepository(t *testing.T) *testRepository {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	repo := &testRepository{
+		t:         t,
+		Directory: filepath.Join(root, "repository"),
+		Remote:    filepath.Join(root, "remotes", "got.git"),
+	}
+
+	setTestEnv(t, "HOME", root)
+	setTestEnv(t, "GIT_CONFIG_NOSYSTEM", "1")
+	setTestEnv(t, "GIT_AUTHOR_NAME", "Test")
+	setTestEnv(t, "GIT_AUTHOR_EMAIL", "test@example.com")
+	setTestEnv(t, "GIT_COMMITTER_NAME", "Test")
+	setTestEnv(t, "GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repo.runGit(root, "init", "--quiet", "--bare", repo.Remote)
+	repo.runGit(root, "init", "--quiet", repo.Directory)
+	repo.Git("symbolic-ref", "HEAD", "refs/heads/main")
+	repo.Git("config", "commit.gpgsign", "false")
+	repo.Commit("Initial commit")
+	repo.Git("remote", "add", "origin", repo.Remote)
+	repo.Git("push", "--quiet", "-u", "origin", "main")
+
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(repo.Directory); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(workingDirectory) })
+
+	return repo
+}
+
+// Git runs git command in the repository and returns its trimmed output, failures stop the test
+func (repo *testRepository) Git(args ...string) string {
+	repo.t.Helper()
+	return repo.runGit(repo.Directory, args...)
+}
+
+// Commit creates empty commit on the current branch
+func (repo *testRepository) Commit(message string) {
+	repo.t.Helper()
+	repo.Git("commit", "--quiet", "--allow-empty", "-m", message)
+}
+
+// CreateBranch creates branch with a commit from the current HEAD without switching to it
+func (repo *testRepository) CreateBranch(branchName string) {
+	repo.t.Helper()
+	currentBranchName := repo.CurrentBranch()
+	repo.Git("checkout", "--quiet", "-b", branchName)
+	repo.Commit("Work on " + branchName)
+	repo.Git("checkout", "--quiet", currentBranchName)
+}
+
+// CreateRemoteBranch creates branch that exists only in origin remote
+func (repo *testRepository) CreateRemoteBranch(branchName string) {
+	repo.t.Helper()
+	repo.CreateBranch(branchName)
+	repo.Git("push", "--quiet", "origin", branchName)
+	repo.Git("branch", "--quiet", "-D", branchName)
+}
+
+// CurrentBranch returns name of the checked out branch
+func (repo *testRepository) CurrentBranch() string {
+	repo.t.Helper()
+	return repo.Git("branch", "--show-current")
+}
+
+// HasBranch checks if local branch exists
+func (repo *testRepository) HasBranch(branchName string) bool {
+	repo.t.Helper()
+	return repo.Git("branch", "--list", branchName) != ""
+}
+
+func (repo *testRepository) runGit(directory string, args ...string) string {
+	repo.t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = directory
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		repo.t.Fatalf("git %s failed: %s\n%s", strings.Join(args, " "), err, output)
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+func setTestEnv(t *testing.T, key string, value string) {
+	previousValue, hasValue := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if hasValue {
+			os.Setenv(key, previousValue)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package git
+
+import "time"
+
+// Worktree is a struct for git worktree
+type Worktree struct {
+	Path     string
+	Head     string
+	Branch   string
+	Bare     bool
+	Detached bool
+	Prunable bool
+}
+
+// BranchInfo is a struct for local branch with its upstream and last commit date
+type BranchInfo struct {
+	Name           string
+	Upstream       string
+	LastCommitDate time.Time
+}
+
+// Commit is a struct for git commit shown in the log
+type Commit struct {
+	Hash    string
+	Subject string
+	Author  string
+	Date    time.Time
+}
@@ -2,52 +2,116 @@ package git
 
 import (
 	"errors"
-	"got/pkg/config"
+	"fmt"
+	"path"
 	"regexp"
 	"strings"
 )
 
+// BranchNamer generates and parses names of branches linked to Jira issues of a project
+type BranchNamer struct {
+	// Separator is placed between issue keys and the rest of branch name
+	Separator string
+	// ProjectCode is a Jira project code that prefixes issue keys
+	ProjectCode string
+}
+
+// IssueKeyPrefix returns prefix of the project issue keys, e.g. 'PC-'
+func (namer BranchNamer) IssueKeyPrefix() string {
+	return namer.ProjectCode + "-"
+}
+
 // GenerateBranchName generates branch name for issue keys and summary
-func GenerateBranchName(issueKeys []string, summary string) (string, error) {
+func (namer BranchNamer) GenerateBranchName(issueKeys []string, summary string) (string, error) {
+	filteredSummary, err := formatBranchNamePart(summary)
+	if err != nil {
+		return "", err
+	}
+	branchNameSubstrings := append(issueKeys, filteredSummary)
+
+	return strings.Join(branchNameSubstrings, namer.Separator), nil
+}
+
+// GenerateBranchNameFromPattern generates branch name replacing {key}, {summary} and {type} placeholders of the pattern,
+// e.g. 'bugfix/{key}/{summary}'. Issue keys should be separated from the rest of the name with the separator,
+// so that they can be found in the branch name.
+func (namer BranchNamer) GenerateBranchNameFromPattern(
+	pattern string, issueKeys []string, summary string, issueType string,
+) (string, error) {
+	if !strings.Contains(pattern, "{key}") {
+		return "", fmt.Errorf("Branch name pattern '%s' does not contain {key} placeholder", pattern)
+	}
+
+	filteredSummary, err := formatBranchNamePart(summary)
+	if err != nil {
+		return "", err
+	}
+	filteredIssueType, err := formatBranchNamePart(issueType)
+	if err != nil {
+		return "", err
+	}
+
+	branchName := strings.NewReplacer(
+		"{key}", strings.Join(issueKeys, namer.Separator),
+		"{summary}", filteredSummary,
+		"{type}", filteredIssueType,
+	).Replace(pattern)
+
+	foundIssueKeys := namer.GetIssueKeysFromBranchName(branchName)
+	if strings.Join(foundIssueKeys, namer.Separator) != strings.Join(issueKeys, namer.Separator) {
+		return "", fmt.Errorf(
+			"Branch name pattern '%s' should separate {key} from the rest of the name with '%s'", pattern, namer.Separator,
+		)
+	}
+
+	return branchName, nil
+}
+
+// ValidateBranchNamePattern checks that branch names generated from the pattern keep issue keys separated
+// from the rest of the name, so that invalid patterns are reported before issues are created
+func (namer BranchNamer) ValidateBranchNamePattern(pattern string) error {
+	_, err := namer.GenerateBranchNameFromPattern(pattern, []string{namer.IssueKeyPrefix() + "1"}, "summary", "task")
+	return err
+}
+
+// formatBranchNamePart returns lower case text without special characters and with spaces replaced by underscores
+func formatBranchNamePart(text string) (string, error) {
 	reg, err := regexp.Compile("[^a-zA-Z0-9 ]+")
 	if err != nil {
 		return "", errors.New("Failed to create regexp for branch name")
 	}
 
-	branchName := reg.ReplaceAllString(summary, "")
+	branchName := reg.ReplaceAllString(text, "")
 	reg, err = regexp.Compile("[ ]")
 	if err != nil {
 		return "", errors.New("Failed to create regexp for branch name with underscores")
 	}
 
-	filteredSummary := strings.ToLower(reg.ReplaceAllString(strings.TrimSpace(branchName), "_"))
-	branchNameSubstrings := append(issueKeys, filteredSummary)
-
-	return strings.Join(branchNameSubstrings, config.Options.IssueBranchSeparator), nil
+	return strings.ToLower(reg.ReplaceAllString(strings.TrimSpace(branchName), "_")), nil
 }
 
 // PrependIssueKeysToBranchName prepends issue keys to branch name
-func PrependIssueKeysToBranchName(issueKeys []string, branchName string) (string, error) {
+func (namer BranchNamer) PrependIssueKeysToBranchName(issueKeys []string, branchName string) (string, error) {
 	branchNameSubstrings := append(issueKeys, branchName)
 
-	return strings.Join(branchNameSubstrings, config.Options.IssueBranchSeparator), nil
+	return strings.Join(branchNameSubstrings, namer.Separator), nil
 }
 
-// RemoveIssueKeysFromBranchName remove issue keys from branch name
-func RemoveIssueKeysFromBranchName(issueKeys []string, branchName string) string {
-	substrings := strings.Split(branchName, config.Options.IssueBranchSeparator)
-	for _, issueKey := range issueKeys {
-		substrings = removeElementFromArray(substrings, issueKey)
+// RemoveIssueKeysFromBranchName removes every occurrence of issue keys from branch name
+func (namer BranchNamer) RemoveIssueKeysFromBranchName(issueKeys []string, branchName string) string {
+	substrings := strings.Split(branchName, namer.Separator)
+	filterFunc := func(substring string) bool {
+		return findElementInArray(issueKeys, substring) == -1
 	}
 
-	return strings.Join(substrings, config.Options.IssueBranchSeparator)
+	return strings.Join(filter(substrings, filterFunc), namer.Separator)
 }
 
 // GetIssueKeysFromBranchName returns list of Jira issue keys accosiated with current branch
-func GetIssueKeysFromBranchName(branchName string) []string {
-	substrings := strings.Split(branchName, config.Options.IssueBranchSeparator)
+func (namer BranchNamer) GetIssueKeysFromBranchName(branchName string) []string {
+	substrings := strings.Split(branchName, namer.Separator)
 
-	issueKeyPrefix := config.GetIssueKeyPrefix()
+	issueKeyPrefix := namer.IssueKeyPrefix()
 	filterFunc := func(substring string) bool {
 		return strings.HasPrefix(substring, issueKeyPrefix)
 	}
@@ -55,6 +119,58 @@ func GetIssueKeysFromBranchName(branchName string) []string {
 	return filter(substrings, filterFunc)
 }
 
+// FindIssueKeysByPatterns returns unique issue keys of branch name matching any of regular expressions.
+// The first capture group of a pattern is used as the issue key when the pattern has groups, so that patterns
+// can require separators around the key, e.g. '(?:^|/)(PC-[0-9]+)(?:/|$)'. Keys are searched again right after
+// the previous key, so separators between consecutive keys match both of them.
+func FindIssueKeysByPatterns(branchName string, patterns []string) ([]string, error) {
+	var issueKeys []string
+	for _, pattern := range patterns {
+		reg, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid issue key pattern '%s': %s", pattern, err.Error())
+		}
+
+		for _, issueKey := range findAllIssueKeys(reg, branchName) {
+			if findElementInArray(issueKeys, issueKey) == -1 {
+				issueKeys = append(issueKeys, issueKey)
+			}
+		}
+	}
+
+	return issueKeys, nil
+}
+
+// findAllIssueKeys returns the first group of every match. Matches are searched in the whole branch name,
+// so that anchors like '^' and '\b' keep their meaning, and the skipped part is matched literally.
+func findAllIssueKeys(reg *regexp.Regexp, branchName string) []string {
+	if reg.NumSubexp() == 0 {
+		return reg.FindAllString(branchName, -1)
+	}
+
+	var issueKeys []string
+	searchReg := reg
+	for {
+		loc := searchReg.FindStringSubmatchIndex(branchName)
+		if loc == nil || loc[2] < 0 || loc[3] == loc[2] {
+			return issueKeys
+		}
+		issueKeys = append(issueKeys, branchName[loc[2]:loc[3]])
+
+		searchReg = regexp.MustCompile(`\A` + regexp.QuoteMeta(branchName[:loc[3]]) + `(?s:.*?)(?:` + reg.String() + `)`)
+	}
+}
+
+// MatchBranchName checks if branch name matches any of glob patterns
+func MatchBranchName(branchName string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, branchName); matched {
+			return true
+		}
+	}
+	return false
+}
+
 func filter(stringsArr []string, filterFunc func(string) bool) (filteredArr []string) {
 	for _, str := range stringsArr {
 		if filterFunc(str) {
@@ -64,15 +180,6 @@ func filter(stringsArr []string, filterFunc func(string) bool) (filteredArr []st
 	return
 }
 
-func removeElementFromArray(stringsArr []string, stringToRemove string) (filteredArr []string) {
-	i := findElementInArray(stringsArr, stringToRemove)
-	if i == -1 {
-		return stringsArr
-	}
-
-	return append(stringsArr[:i], stringsArr[i+1:]...)
-}
-
 func findElementInArray(arr []string, stringToSearch string) int {
 	for i, element := range arr {
 		if stringToSearch == element {
@@ -1,11 +1,14 @@
 package git
 
 import (
+	"strings"
 	"testing"
 )
 
+var testBranchNamer = BranchNamer{Separator: "/", ProjectCode: "PC"}
+
 func TestGenerateBranchName_WithoutJiraIssueKeys(t *testing.T) {
-	branchName, err := GenerateBranchName([]string{}, "Test string  data")
+	branchName, err := testBranchNamer.GenerateBranchName([]string{}, "Test string  data")
 
 	if err != nil {
 		t.Errorf("GenerateBranchName without Jira issue keys returned error %+v", err.Error())
@@ -18,7 +21,7 @@ func TestGenerateBranchName_WithoutJiraIssueKeys(t *testing.T) {
 }
 
 func TestGenerateBranchName_WithSeveralJiraIssueKeys(t *testing.T) {
-	branchName, err := GenerateBranchName([]string{"PC-1234", "PC-345"}, "Test string  data")
+	branchName, err := testBranchNamer.GenerateBranchName([]string{"PC-1234", "PC-345"}, "Test string  data")
 
 	if err != nil {
 		t.Errorf("GenerateBranchName without Jira issue keys returned error %+v", err.Error())
@@ -31,7 +34,7 @@ func TestGenerateBranchName_WithSeveralJiraIssueKeys(t *testing.T) {
 }
 
 func TestPrependIssueKeysToBranchName_WithoutJiraIssueKeys(t *testing.T) {
-	branchName, err := PrependIssueKeysToBranchName([]string{}, "test_branch_name")
+	branchName, err := testBranchNamer.PrependIssueKeysToBranchName([]string{}, "test_branch_name")
 
 	if err != nil {
 		t.Errorf("GenerateBranchName without Jira issue keys returned error %+v", err.Error())
@@ -44,7 +47,7 @@ func TestPrependIssueKeysToBranchName_WithoutJiraIssueKeys(t *testing.T) {
 }
 
 func TestPrependIssueKeysToBranchName_WithJiraIssueKeys(t *testing.T) {
-	branchName, err := PrependIssueKeysToBranchName([]string{"PC-123", "PC-345"}, "test_branch_name")
+	branchName, err := testBranchNamer.PrependIssueKeysToBranchName([]string{"PC-123", "PC-345"}, "test_branch_name")
 
 	if err != nil {
 		t.Errorf("PrependIssueKeysToBranchName without Jira issue keys returned error %+v", err.Error())
@@ -61,20 +64,154 @@ func TestPrependIssueKeysToBranchName_WithJiraIssueKeys(t *testing.T) {
 }
 
 func TestRemoveIssueKeysFromBranchName_WithoutJiraIssueKeys(t *testing.T) {
+	branchName := testBranchNamer.RemoveIssueKeysFromBranchName([]string{}, "PC-123/test_branch_name")
 
+	expectedBranchName := "PC-123/test_branch_name"
+	if branchName != expectedBranchName {
+		t.Errorf("RemoveIssueKeysFromBranchName without Jira issue keys returned %+v, want %+v", branchName, expectedBranchName)
+	}
 }
 
 func TestRemoveIssueKeysFromBranchName_WithJiraIssueKeys(t *testing.T) {
+	branchName := testBranchNamer.RemoveIssueKeysFromBranchName([]string{"PC-123", "PC-345"}, "PC-123/PC-345/test_branch_name")
 
+	expectedBranchName := "test_branch_name"
+	if branchName != expectedBranchName {
+		t.Errorf("RemoveIssueKeysFromBranchName with Jira issue keys returned %+v, want %+v", branchName, expectedBranchName)
+	}
 }
 
 func TestRemoveIssueKeysFromBranchName_WithFirstJiraIssueKey(t *testing.T) {
+	branchName := testBranchNamer.RemoveIssueKeysFromBranchName([]string{"PC-123"}, "PC-123/PC-345/test_branch_name")
 
+	expectedBranchName := "PC-345/test_branch_name"
+	if branchName != expectedBranchName {
+		t.Errorf("RemoveIssueKeysFromBranchName with first Jira issue key returned %+v, want %+v", branchName, expectedBranchName)
+	}
 }
+
 func TestRemoveIssueKeysFromBranchName_WithLastJiraIssueKey(t *testing.T) {
+	branchName := testBranchNamer.RemoveIssueKeysFromBranchName([]string{"PC-345"}, "PC-123/PC-345/test_branch_name")
 
+	expectedBranchName := "PC-123/test_branch_name"
+	if branchName != expectedBranchName {
+		t.Errorf("RemoveIssueKeysFromBranchName with last Jira issue key returned %+v, want %+v", branchName, expectedBranchName)
+	}
 }
 
 func TestRemoveIssueKeysFromBranchName_WithDuplicateJiraIssueKey(t *testing.T) {
+	branchName := testBranchNamer.RemoveIssueKeysFromBranchName([]string{"PC-123"}, "PC-123/PC-345/PC-123/test_branch_name")
+
+	expectedBranchName := "PC-345/test_branch_name"
+	if branchName != expectedBranchName {
+		t.Errorf("RemoveIssueKeysFromBranchName with duplicate Jira issue key returned %+v, want %+v", branchName, expectedBranchName)
+	}
+}
+
+func TestFindIssueKeysByPatterns(t *testing.T) {
+	issueKeys, err := FindIssueKeysByPatterns("PC-12/OPS-3/fix_PC-12_login", []string{"PC-[0-9]+", "OPS-[0-9]+"})
+
+	if err != nil {
+		t.Errorf("FindIssueKeysByPatterns returned error %+v", err.Error())
+	}
+
+	if strings.Join(issueKeys, ",") != "PC-12,OPS-3" {
+		t.Errorf("FindIssueKeysByPatterns returned %+v, want [PC-12 OPS-3]", issueKeys)
+	}
+}
 
+func TestFindIssueKeysByPatterns_Anchored(t *testing.T) {
+	patterns := []string{"(?:^|/)(PC-[0-9]+)(?:/|$)"}
+	tests := []struct {
+		branchName string
+		want       string
+	}{
+		{"PC-12/PC-13/fix_login", "PC-12,PC-13"},
+		{"feature/PC-12", "PC-12"},
+		{"fix_XPC-12/login", ""},
+		{"PC-12x/login", ""},
+		{"revert_PC-12_login", ""},
+	}
+
+	for _, tt := range tests {
+		issueKeys, err := FindIssueKeysByPatterns(tt.branchName, patterns)
+		if err != nil {
+			t.Errorf("FindIssueKeysByPatterns returned error %+v", err.Error())
+		}
+		if strings.Join(issueKeys, ",") != tt.want {
+			t.Errorf("FindIssueKeysByPatterns of '%s' returned %+v, want %s", tt.branchName, issueKeys, tt.want)
+		}
+	}
+}
+
+func TestFindIssueKeysByPatterns_AnchorsInPatterns(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		branchName string
+		want       string
+	}{
+		{"^(PC-[0-9]+)", "PC-1PC-2", "PC-1"},
+		{"^(PC-[0-9]+)", "feature/PC-1", ""},
+		{"\\b(PC-[0-9]+)", "PC-1PC-2", "PC-1"},
+		{"\\b(PC-[0-9]+)", "PC-1/PC-2", "PC-1,PC-2"},
+	}
+
+	for _, tt := range tests {
+		issueKeys, err := FindIssueKeysByPatterns(tt.branchName, []string{tt.pattern})
+		if err != nil {
+			t.Errorf("FindIssueKeysByPatterns returned error %+v", err.Error())
+		}
+		if strings.Join(issueKeys, ",") != tt.want {
+			t.Errorf("FindIssueKeysByPatterns of '%s' with '%s' returned %+v, want %s", tt.branchName, tt.pattern, issueKeys, tt.want)
+		}
+	}
+}
+
+func TestMatchBranchName(t *testing.T) {
+	patterns := []string{"main", "release/*"}
+
+	if !MatchBranchName("release/1.2", patterns) {
+		t.Errorf("MatchBranchName did not match 'release/1.2' with %+v", patterns)
+	}
+	if MatchBranchName("feature/main", patterns) {
+		t.Errorf("MatchBranchName matched 'feature/main' with %+v", patterns)
+	}
+}
+
+func TestGenerateBranchNameFromPattern(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		issueKeys  []string
+		want       string
+		wantErrMsg string
+	}{
+		{"bugfix/{key}/{summary}", []string{"PC-12"}, "bugfix/PC-12/fix_login_page", ""},
+		{"{type}/{key}/{summary}", []string{"PC-12", "PC-13"}, "user_story/PC-12/PC-13/fix_login_page", ""},
+		{"bugfix/{summary}", []string{"PC-12"}, "", "does not contain {key} placeholder"},
+		{"bugfix/{key}-{summary}", []string{"PC-12"}, "", "should separate {key} from the rest of the name with '/'"},
+	}
+
+	for _, tt := range tests {
+		branchName, err := testBranchNamer.GenerateBranchNameFromPattern(tt.pattern, tt.issueKeys, "Fix login page!", "User Story")
+		if tt.wantErrMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+				t.Errorf("GenerateBranchNameFromPattern for '%s' returned error %v, want %q", tt.pattern, err, tt.wantErrMsg)
+			}
+			continue
+		}
+		if err != nil || branchName != tt.want {
+			t.Errorf("GenerateBranchNameFromPattern for '%s' returned %q, %v, want %q", tt.pattern, branchName, err, tt.want)
+		}
+	}
+}
+
+func TestValidateBranchNamePattern(t *testing.T) {
+	if err := testBranchNamer.ValidateBranchNamePattern("{type}/{key}/{summary}"); err != nil {
+		t.Errorf("ValidateBranchNamePattern returned error %v for valid pattern", err)
+	}
+	for _, pattern := range []string{"{summary}", "{key}-{summary}"} {
+		if err := testBranchNamer.ValidateBranchNamePattern(pattern); err == nil {
+			t.Errorf("ValidateBranchNamePattern returned no error for '%s'", pattern)
+		}
+	}
 }
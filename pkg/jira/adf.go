@@ -0,0 +1,686 @@
+package jira
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ADFNode is a node of Atlassian Document Format document used by rich text fields like issue description
+type ADFNode struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []ADFNode              `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []ADFMark              `json:"marks,omitempty"`
+}
+
+// ADFMark is a text formatting of Atlassian Document Format text node
+type ADFMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+var (
+	markdownHeadingRegexp  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	markdownRuleRegexp     = regexp.MustCompile(`^ {0,3}(?:(?:\*\s*){3,}|(?:-\s*){3,}|(?:_\s*){3,})$`)
+	markdownFenceRegexp    = regexp.MustCompile("^ {0,3}(```+|~~~+)\\s*([^`\\s]*)")
+	markdownListItemRegexp = regexp.MustCompile(`^( *)([-*+]|\d{1,9}[.)])(?: +(.*))?$`)
+	markdownQuoteRegexp    = regexp.MustCompile(`^ {0,3}> ?`)
+)
+
+// MarkdownToADF converts Markdown text to Atlassian Document Format document.
+// Headings, paragraphs, nested lists, block quotes, fenced code blocks, rules and
+// bold, italic, strikethrough, inline code and link formatting are supported.
+func MarkdownToADF(markdown string) ADFNode {
+	markdown = strings.ReplaceAll(markdown, "\r\n", "\n")
+	markdown = strings.ReplaceAll(markdown, "\t", "    ")
+
+	return ADFNode{
+		Type:    "doc",
+		Version: 1,
+		Content: parseMarkdownBlocks(strings.Split(markdown, "\n")),
+	}
+}
+
+func parseMarkdownBlocks(lines []string) []ADFNode {
+	var nodes []ADFNode
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case markdownFenceRegexp.MatchString(line):
+			var node ADFNode
+			node, i = parseMarkdownCodeBlock(lines, i)
+			nodes = append(nodes, node)
+		case markdownHeadingRegexp.MatchString(line):
+			matches := markdownHeadingRegexp.FindStringSubmatch(line)
+			nodes = append(nodes, ADFNode{
+				Type:    "heading",
+				Attrs:   map[string]interface{}{"level": len(matches[1])},
+				Content: parseMarkdownInline(matches[2], nil),
+			})
+			i++
+		case markdownRuleRegexp.MatchString(line):
+			nodes = append(nodes, ADFNode{Type: "rule"})
+			i++
+		case markdownQuoteRegexp.MatchString(line):
+			var quoteLines []string
+			for ; i < len(lines) && markdownQuoteRegexp.MatchString(lines[i]); i++ {
+				quoteLines = append(quoteLines, markdownQuoteRegexp.ReplaceAllString(lines[i], ""))
+			}
+			nodes = append(nodes, ADFNode{Type: "blockquote", Content: parseMarkdownBlocks(quoteLines)})
+		case markdownListItemRegexp.MatchString(line):
+			var node ADFNode
+			node, i = parseMarkdownList(lines, i)
+			nodes = append(nodes, node)
+		default:
+			var node ADFNode
+			node, i = parseMarkdownParagraph(lines, i)
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+// parseMarkdownCodeBlock returns code block starting with a fence on the line and index of the line after it
+func parseMarkdownCodeBlock(lines []string, start int) (ADFNode, int) {
+	matches := markdownFenceRegexp.FindStringSubmatch(lines[start])
+	fence := matches[1]
+
+	var codeLines []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) && strings.Trim(strings.TrimSpace(lines[i]), fence[:1]) == "" {
+			i++
+			break
+		}
+		codeLines = append(codeLines, lines[i])
+	}
+
+	node := ADFNode{Type: "codeBlock"}
+	if matches[2] != "" {
+		node.Attrs = map[string]interface{}{"language": matches[2]}
+	}
+	if code := strings.Join(codeLines, "\n"); code != "" {
+		node.Content = []ADFNode{{Type: "text", Text: code}}
+	}
+
+	return node, i
+}
+
+// parseMarkdownList returns list starting with an item on the line and index of the line after it
+func parseMarkdownList(lines []string, start int) (ADFNode, int) {
+	firstItem := markdownListItemRegexp.FindStringSubmatch(lines[start])
+	listIndent := len(firstItem[1])
+	ordered := isOrderedListMarker(firstItem[2])
+
+	list := ADFNode{Type: "bulletList"}
+	if ordered {
+		list.Type = "orderedList"
+		if order, _ := strconv.Atoi(strings.TrimRight(firstItem[2], ".)")); order > 1 {
+			list.Attrs = map[string]interface{}{"order": order}
+		}
+	}
+
+	i := start
+	for i < len(lines) {
+		item := markdownListItemRegexp.FindStringSubmatch(lines[i])
+		if item == nil || len(item[1]) < listIndent || isOrderedListMarker(item[2]) != ordered {
+			break
+		}
+		contentIndent := len(item[1]) + len(item[2]) + 1
+
+		itemLines := []string{item[3]}
+		i++
+		for i < len(lines) {
+			line := lines[i]
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			if strings.TrimSpace(line) == "" {
+				next := i + 1
+				for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+					next++
+				}
+				if next == len(lines) {
+					i = next
+					break
+				}
+				nextIndent := len(lines[next]) - len(strings.TrimLeft(lines[next], " "))
+				if nextIndent < contentIndent {
+					break
+				}
+				itemLines = append(itemLines, "")
+				i++
+				continue
+			}
+			// items indented less than the item content are siblings or items of parent lists
+			if markdownListItemRegexp.MatchString(line) && indent < contentIndent {
+				break
+			}
+			if indent > listIndent {
+				itemLines = append(itemLines, line[minInt(indent, contentIndent):])
+				i++
+				continue
+			}
+			// lines without indentation continue the paragraph of the item
+			if itemLines[len(itemLines)-1] != "" && !isMarkdownBlockStart(line) {
+				itemLines = append(itemLines, strings.TrimSpace(line))
+				i++
+				continue
+			}
+			break
+		}
+
+		// list items should start with a paragraph or a code block
+		content := parseMarkdownBlocks(itemLines)
+		if len(content) == 0 || (content[0].Type != "paragraph" && content[0].Type != "codeBlock") {
+			content = append([]ADFNode{{Type: "paragraph"}}, content...)
+		}
+		list.Content = append(list.Content, ADFNode{Type: "listItem", Content: content})
+
+		// blank lines between items of the same list are skipped
+		next := i
+		for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+			next++
+		}
+		if next < len(lines) && markdownListItemRegexp.MatchString(lines[next]) {
+			i = next
+		}
+	}
+
+	return list, i
+}
+
+// parseMarkdownParagraph returns paragraph starting on the line and index of the line after it
+func parseMarkdownParagraph(lines []string, start int) (ADFNode, int) {
+	var content []ADFNode
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || (i > start && isMarkdownBlockStart(line)) {
+			break
+		}
+
+		if i > start && len(content) > 0 {
+			last := &content[len(content)-1]
+			if last.Type == "text" && last.Marks == nil {
+				last.Text += " "
+			} else if last.Type != "hardBreak" {
+				content = append(content, ADFNode{Type: "text", Text: " "})
+			}
+		}
+		content = append(content, parseMarkdownInline(strings.TrimSpace(line), nil)...)
+		// two trailing spaces or a backslash break the line
+		if strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			if last := &content[len(content)-1]; last.Type == "text" {
+				last.Text = strings.TrimSuffix(last.Text, "\\")
+			}
+			content = append(content, ADFNode{Type: "hardBreak"})
+		}
+	}
+
+	return ADFNode{Type: "paragraph", Content: mergeADFTextNodes(trimHardBreaks(content))}, i
+}
+
+func isMarkdownBlockStart(line string) bool {
+	return markdownFenceRegexp.MatchString(line) ||
+		markdownHeadingRegexp.MatchString(line) ||
+		markdownRuleRegexp.MatchString(line) ||
+		markdownQuoteRegexp.MatchString(line) ||
+		markdownListItemRegexp.MatchString(line)
+}
+
+func isOrderedListMarker(marker string) bool {
+	return strings.HasSuffix(marker, ".") || strings.HasSuffix(marker, ")")
+}
+
+// parseMarkdownInline returns text nodes of Markdown text with formatting converted to marks
+func parseMarkdownInline(text string, marks []ADFMark) []ADFNode {
+	var nodes []ADFNode
+	var plain strings.Builder
+	flush := func() {
+		if plain.Len() > 0 {
+			nodes = append(nodes, ADFNode{Type: "text", Text: plain.String(), Marks: marks})
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_{}[]()#+-.!~>|<", rune(rest[1])):
+			plain.WriteByte(rest[1])
+			i += 2
+			continue
+		case rest[0] == '`':
+			fence := rest[:len(rest)-len(strings.TrimLeft(rest, "`"))]
+			if end := strings.Index(rest[len(fence):], fence); end > 0 {
+				flush()
+				code := rest[len(fence) : len(fence)+end]
+				if strings.TrimSpace(code) != "" {
+					code = strings.TrimSpace(code)
+				}
+				nodes = append(nodes, ADFNode{Type: "text", Text: code, Marks: codeMarks(marks)})
+				i += len(fence)*2 + end
+				continue
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__") || strings.HasPrefix(rest, "~~"):
+			markType := "strong"
+			if rest[0] == '~' {
+				markType = "strike"
+			}
+			if end := findMarkdownDelimiter(rest[2:], rest[:2]); end > 0 {
+				flush()
+				nodes = append(nodes, parseMarkdownInline(rest[2:2+end], appendMark(marks, ADFMark{Type: markType}))...)
+				i += 4 + end
+				continue
+			}
+		case rest[0] == '*' || rest[0] == '_':
+			// underscores inside words like snake_case are not emphasis
+			if rest[0] == '_' && i > 0 && isWordByte(text[i-1]) {
+				break
+			}
+			if end := findMarkdownDelimiter(rest[1:], rest[:1]); end > 0 {
+				if rest[0] == '_' && 2+end < len(rest) && isWordByte(rest[2+end]) {
+					break
+				}
+				flush()
+				nodes = append(nodes, parseMarkdownInline(rest[1:1+end], appendMark(marks, ADFMark{Type: "em"}))...)
+				i += 2 + end
+				continue
+			}
+		case rest[0] == '[':
+			if label, href, length := parseMarkdownLink(rest); length > 0 {
+				flush()
+				linkMark := ADFMark{Type: "link", Attrs: map[string]interface{}{"href": href}}
+				nodes = append(nodes, parseMarkdownInline(label, appendMark(marks, linkMark))...)
+				i += length
+				continue
+			}
+		case rest[0] == '<':
+			if end := strings.IndexByte(rest, '>'); end > 0 && isURL(rest[1:end]) {
+				flush()
+				href := rest[1:end]
+				linkMark := ADFMark{Type: "link", Attrs: map[string]interface{}{"href": href}}
+				nodes = append(nodes, ADFNode{Type: "text", Text: href, Marks: appendMark(marks, linkMark)})
+				i += end + 1
+				continue
+			}
+		}
+
+		plain.WriteByte(rest[0])
+		i++
+	}
+	flush()
+
+	return mergeADFTextNodes(nodes)
+}
+
+// findMarkdownDelimiter returns index of closing delimiter in text or -1 for unclosed formatting
+func findMarkdownDelimiter(text string, delimiter string) int {
+	if text == "" || text[0] == ' ' {
+		return -1
+	}
+	for i := 1; i+len(delimiter) <= len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+			continue
+		case '`':
+			// delimiters inside inline code do not close formatting
+			if end := strings.IndexByte(text[i+1:], '`'); end >= 0 {
+				i += end + 1
+			}
+			continue
+		}
+		if !strings.HasPrefix(text[i:], delimiter) {
+			continue
+		}
+		// single delimiters are not closed by halves of double ones, e.g. '*a **b** c*'
+		if len(delimiter) == 1 && i+1 < len(text) && text[i+1] == delimiter[0] {
+			i++
+			continue
+		}
+		if text[i-1] != ' ' {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseMarkdownLink returns label and URL of '[label](url)' link at the start of text and length of the link
+func parseMarkdownLink(text string) (string, string, int) {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if i+1 >= len(text) || text[i+1] != '(' {
+				return "", "", 0
+			}
+			end := strings.IndexByte(text[i+2:], ')')
+			if end < 0 {
+				return "", "", 0
+			}
+			href := strings.TrimSpace(text[i+2 : i+2+end])
+			// optional link title is not supported by Jira and is dropped
+			if space := strings.IndexByte(href, ' '); space > 0 {
+				href = href[:space]
+			}
+			if href == "" || i == 1 {
+				return "", "", 0
+			}
+			return text[1:i], strings.Trim(href, "<>"), i + 3 + end
+		}
+	}
+	return "", "", 0
+}
+
+func isURL(text string) bool {
+	return (strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://") || strings.HasPrefix(text, "mailto:")) &&
+		!strings.ContainsAny(text, " <")
+}
+
+func isWordByte(char byte) bool {
+	return char == '_' || char >= '0' && char <= '9' || char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= 0x80
+}
+
+// appendMark returns copy of marks with the mark added, so sibling nodes do not share the slice
+func appendMark(marks []ADFMark, mark ADFMark) []ADFMark {
+	result := make([]ADFMark, 0, len(marks)+1)
+	result = append(result, marks...)
+	return append(result, mark)
+}
+
+// codeMarks returns marks of inline code, which can be combined with links only
+func codeMarks(marks []ADFMark) []ADFMark {
+	result := []ADFMark{}
+	for _, mark := range marks {
+		if mark.Type == "link" {
+			result = append(result, mark)
+		}
+	}
+	return append(result, ADFMark{Type: "code"})
+}
+
+// mergeADFTextNodes joins adjacent text nodes with equal marks
+func mergeADFTextNodes(nodes []ADFNode) []ADFNode {
+	var merged []ADFNode
+	for _, node := range nodes {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if node.Type == "text" && last.Type == "text" && equalADFMarks(node.Marks, last.Marks) {
+				last.Text += node.Text
+				continue
+			}
+		}
+		merged = append(merged, node)
+	}
+	return merged
+}
+
+func equalADFMarks(a []ADFMark, b []ADFMark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || a[i].Attrs["href"] != b[i].Attrs["href"] {
+			return false
+		}
+	}
+	return true
+}
+
+func trimHardBreaks(nodes []ADFNode) []ADFNode {
+	for len(nodes) > 0 && nodes[len(nodes)-1].Type == "hardBreak" {
+		nodes = nodes[:len(nodes)-1]
+	}
+	return nodes
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// ADFToMarkdown converts Atlassian Document Format document to Markdown text.
+// Nodes without Markdown equivalent like media are skipped, panels are converted to block quotes.
+func ADFToMarkdown(document ADFNode) string {
+	return strings.TrimSpace(renderADFBlocks(document.Content, false))
+}
+
+// renderADFBlocks returns Markdown of block nodes separated with empty lines, lists nested in list items are kept tight
+func renderADFBlocks(nodes []ADFNode, isListItem bool) string {
+	var markdown strings.Builder
+	for _, node := range nodes {
+		block := renderADFBlock(node)
+		if block == "" {
+			continue
+		}
+		if markdown.Len() > 0 {
+			if isListItem && (node.Type == "bulletList" || node.Type == "orderedList") {
+				markdown.WriteString("\n")
+			} else {
+				markdown.WriteString("\n\n")
+			}
+		}
+		markdown.WriteString(block)
+	}
+	return markdown.String()
+}
+
+func renderADFBlock(node ADFNode) string {
+	switch node.Type {
+	case "paragraph":
+		return renderADFInline(node.Content)
+	case "heading":
+		level := 1
+		if value, ok := node.Attrs["level"].(float64); ok {
+			level = int(value)
+		} else if value, ok := node.Attrs["level"].(int); ok {
+			level = value
+		}
+		return strings.Repeat("#", level) + " " + renderADFInline(node.Content)
+	case "codeBlock":
+		language, _ := node.Attrs["language"].(string)
+		code := getADFPlainText(node.Content)
+		fence := "```"
+		for strings.Contains(code, fence) {
+			fence += "`"
+		}
+		return fence + language + "\n" + code + "\n" + fence
+	case "blockquote", "panel":
+		return prefixMarkdownLines(renderADFBlocks(node.Content, false), "> ", "> ")
+	case "rule":
+		return "---"
+	case "bulletList", "orderedList":
+		return renderADFList(node)
+	case "table":
+		return renderADFTable(node)
+	case "mediaSingle", "mediaGroup":
+		return ""
+	}
+
+	if len(node.Content) > 0 && isADFInlineNode(node.Content[0]) {
+		return renderADFInline(node.Content)
+	}
+	return renderADFBlocks(node.Content, false)
+}
+
+func renderADFList(list ADFNode) string {
+	order := 1
+	if value, ok := list.Attrs["order"].(float64); ok {
+		order = int(value)
+	} else if value, ok := list.Attrs["order"].(int); ok {
+		order = value
+	}
+
+	var items []string
+	for i, item := range list.Content {
+		marker := "- "
+		if list.Type == "orderedList" {
+			marker = strconv.Itoa(order+i) + ". "
+		}
+		items = append(items, prefixMarkdownLines(renderADFBlocks(item.Content, true), marker, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+func renderADFTable(table ADFNode) string {
+	var rows []string
+	for i, row := range table.Content {
+		var cells []string
+		for _, cell := range row.Content {
+			var paragraphs []string
+			for _, block := range cell.Content {
+				paragraphs = append(paragraphs, strings.ReplaceAll(renderADFBlock(block), "\n", " "))
+			}
+			cells = append(cells, strings.ReplaceAll(strings.Join(paragraphs, "<br>"), "|", "\\|"))
+		}
+		rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+		// Markdown tables require a header row, the first row is used as a header even when it has no header cells
+		if i == 0 {
+			rows = append(rows, "|"+strings.Repeat(" --- |", len(cells)))
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+func renderADFInline(nodes []ADFNode) string {
+	var markdown strings.Builder
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			markdown.WriteString(renderADFText(node))
+		case "hardBreak":
+			markdown.WriteString("\\\n")
+		case "inlineCard":
+			if url, ok := node.Attrs["url"].(string); ok {
+				markdown.WriteString("<" + url + ">")
+			}
+		case "mention", "emoji", "status":
+			if text, ok := node.Attrs["text"].(string); ok {
+				markdown.WriteString(text)
+			} else if shortName, ok := node.Attrs["shortName"].(string); ok {
+				markdown.WriteString(shortName)
+			}
+		default:
+			markdown.WriteString(renderADFInline(node.Content))
+		}
+	}
+	return markdown.String()
+}
+
+// renderADFText returns text with Markdown formatting of its marks, spaces are moved out of formatting delimiters
+func renderADFText(node ADFNode) string {
+	text := node.Text
+	isCode := false
+	for _, mark := range node.Marks {
+		isCode = isCode || mark.Type == "code"
+	}
+	if isCode {
+		fence := "`"
+		for strings.Contains(text, fence) {
+			fence += "`"
+		}
+		if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+			text = " " + text + " "
+		}
+		text = fence + text + fence
+	} else {
+		text = escapeMarkdown(text)
+	}
+
+	content := strings.TrimSpace(text)
+	if content == "" {
+		return text
+	}
+	leading := text[:strings.Index(text, content)]
+	trailing := text[len(leading)+len(content):]
+
+	var href string
+	for i := len(node.Marks) - 1; i >= 0; i-- {
+		switch node.Marks[i].Type {
+		case "strong":
+			content = "**" + content + "**"
+		case "em":
+			content = "*" + content + "*"
+		case "strike":
+			content = "~~" + content + "~~"
+		case "link":
+			href, _ = node.Marks[i].Attrs["href"].(string)
+		}
+	}
+	if href != "" {
+		content = "[" + content + "](" + href + ")"
+	}
+
+	return leading + content + trailing
+}
+
+// escapeMarkdown escapes characters of inline formatting, underscores inside words are kept as is
+func escapeMarkdown(text string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(text); i++ {
+		char := text[i]
+		switch char {
+		case '\\', '`', '*', '[', ']':
+			escaped.WriteByte('\\')
+		case '_':
+			if i == 0 || i == len(text)-1 || !isWordByte(text[i-1]) || !isWordByte(text[i+1]) {
+				escaped.WriteByte('\\')
+			}
+		case '~':
+			if i+1 < len(text) && text[i+1] == '~' {
+				escaped.WriteByte('\\')
+			}
+		}
+		escaped.WriteByte(char)
+	}
+	return escaped.String()
+}
+
+// prefixMarkdownLines adds prefix to the first line and indent to the rest of non-empty lines
+func prefixMarkdownLines(text string, prefix string, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = prefix + line
+		case line != "":
+			lines[i] = indent + line
+		case strings.TrimSpace(indent) != "":
+			lines[i] = strings.TrimRight(indent, " ")
+		}
+	}
+	return strings.TrimRight(lines[0], " ") + strings.Join(append([]string{""}, lines[1:]...), "\n")
+}
+
+func isADFInlineNode(node ADFNode) bool {
+	switch node.Type {
+	case "text", "hardBreak", "inlineCard", "mention", "emoji", "status", "date":
+		return true
+	}
+	return false
+}
+
+func getADFPlainText(nodes []ADFNode) string {
+	var text strings.Builder
+	for _, node := range nodes {
+		text.WriteString(node.Text)
+		text.WriteString(getADFPlainText(node.Content))
+	}
+	return text.String()
+}
@@ -0,0 +1,164 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarkdownToADF(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			"paragraphs",
+			"First line\nsecond line\n\nNext paragraph",
+			`[{"type":"paragraph","content":[{"type":"text","text":"First line second line"}]},` +
+				`{"type":"paragraph","content":[{"type":"text","text":"Next paragraph"}]}]`,
+		},
+		{
+			"hard break",
+			"Line  \nbreak",
+			`[{"type":"paragraph","content":[{"type":"text","text":"Line"},{"type":"hardBreak"},{"type":"text","text":"break"}]}]`,
+		},
+		{
+			"heading and rule",
+			"## Steps ##\n---",
+			`[{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Steps"}]},{"type":"rule"}]`,
+		},
+		{
+			"inline formatting",
+			"**bold** *em* ~~old~~ `a*b` snake_case_name",
+			`[{"type":"paragraph","content":[` +
+				`{"type":"text","text":"bold","marks":[{"type":"strong"}]},{"type":"text","text":" "},` +
+				`{"type":"text","text":"em","marks":[{"type":"em"}]},{"type":"text","text":" "},` +
+				`{"type":"text","text":"old","marks":[{"type":"strike"}]},{"type":"text","text":" "},` +
+				`{"type":"text","text":"a*b","marks":[{"type":"code"}]},{"type":"text","text":" snake_case_name"}]}]`,
+		},
+		{
+			"nested formatting",
+			"*a **b** c*",
+			`[{"type":"paragraph","content":[{"type":"text","text":"a ","marks":[{"type":"em"}]},` +
+				`{"type":"text","text":"b","marks":[{"type":"em"},{"type":"strong"}]},` +
+				`{"type":"text","text":" c","marks":[{"type":"em"}]}]}]`,
+		},
+		{
+			"links",
+			"See [the **docs**](https://example.com/docs \"Docs\") or <https://example.com>",
+			`[{"type":"paragraph","content":[{"type":"text","text":"See "},` +
+				`{"type":"text","text":"the ","marks":[{"type":"link","attrs":{"href":"https://example.com/docs"}}]},` +
+				`{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://example.com/docs"}},{"type":"strong"}]},` +
+				`{"type":"text","text":" or "},` +
+				`{"type":"text","text":"https://example.com","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]}]}]`,
+		},
+		{
+			"unclosed formatting and escapes",
+			"2 * 3 and \\*not em\\* [not a link]",
+			`[{"type":"paragraph","content":[{"type":"text","text":"2 * 3 and *not em* [not a link]"}]}]`,
+		},
+		{
+			"code block",
+			"```go\nfunc main() {\n\t*x = 1\n}\n```\nafter",
+			`[{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"func main() {\n    *x = 1\n}"}]},` +
+				`{"type":"paragraph","content":[{"type":"text","text":"after"}]}]`,
+		},
+		{
+			"nested lists",
+			"- one\n  continued\n- two\n  1. first\n  2. second\n\n- three",
+			`[{"type":"bulletList","content":[` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"one continued"}]}]},` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"two"}]},` +
+				`{"type":"orderedList","content":[` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"first"}]}]},` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"second"}]}]}]}]},` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"three"}]}]}]}]`,
+		},
+		{
+			"ordered list start and list end",
+			"3. third\n4) other list\n\nText",
+			`[{"type":"orderedList","attrs":{"order":3},"content":[` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"third"}]}]},` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"other list"}]}]}]},` +
+				`{"type":"paragraph","content":[{"type":"text","text":"Text"}]}]`,
+		},
+		{
+			"block quote",
+			"> quoted\n> - item",
+			`[{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"quoted"}]},` +
+				`{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"item"}]}]}]}]}]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := MarkdownToADF(test.markdown)
+			if document.Type != "doc" || document.Version != 1 {
+				t.Errorf("MarkdownToADF() = %s document version %d, want doc version 1", document.Type, document.Version)
+			}
+
+			content, err := json.Marshal(document.Content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != test.want {
+				t.Errorf("MarkdownToADF() content =\n%s\nwant\n%s", content, test.want)
+			}
+		})
+	}
+}
+
+func TestADFToMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{
+			"formatting",
+			`{"type":"doc","version":1,"content":[{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Steps"}]},` +
+				`{"type":"paragraph","content":[{"type":"text","text":"bold ","marks":[{"type":"strong"}]},` +
+				`{"type":"text","text":"site","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},` +
+				`{"type":"text","text":" a*b snake_case "},{"type":"text","text":"x` + "`" + `y","marks":[{"type":"code"}]},` +
+				`{"type":"hardBreak"},{"type":"mention","attrs":{"id":"1","text":"@Ann"}}]}]}`,
+			"## Steps\n\n**bold** [site](https://example.com) a\\*b snake_case ``x`y``\\\n@Ann",
+		},
+		{
+			"lists and code",
+			`{"type":"doc","version":1,"content":[{"type":"orderedList","attrs":{"order":2},"content":[` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"one"}]},` +
+				`{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"nested"}]}]}]}]},` +
+				`{"type":"listItem","content":[{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"a()\nb()"}]}]}]},` +
+				`{"type":"mediaSingle","content":[{"type":"media","attrs":{"id":"1"}}]},` +
+				`{"type":"panel","content":[{"type":"paragraph","content":[{"type":"text","text":"Note"}]},{"type":"paragraph","content":[{"type":"text","text":"More"}]}]}]}`,
+			"2. one\n   - nested\n3. ```go\n   a()\n   b()\n   ```\n\n> Note\n>\n> More",
+		},
+		{
+			"table",
+			`{"type":"doc","version":1,"content":[{"type":"table","content":[` +
+				`{"type":"tableRow","content":[{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Key"}]}]},` +
+				`{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Value"}]}]}]},` +
+				`{"type":"tableRow","content":[{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"a|b"}]}]},` +
+				`{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"1"}]}]}]}]}]}`,
+			"| Key | Value |\n| --- | --- |\n| a\\|b | 1 |",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var document ADFNode
+			if err := json.Unmarshal([]byte(test.document), &document); err != nil {
+				t.Fatal(err)
+			}
+			if markdown := ADFToMarkdown(document); markdown != test.want {
+				t.Errorf("ADFToMarkdown() =\n%s\nwant\n%s", markdown, test.want)
+			}
+		})
+	}
+
+	markdown := "# Title\n\nText with **bold**, *em*, ~~old~~ and `code` [link](https://example.com)\n\n" +
+		"- one\n  - nested\n- two\n\n> quote\n\n---"
+	if result := ADFToMarkdown(MarkdownToADF(markdown)); result != markdown {
+		t.Errorf("ADFToMarkdown(MarkdownToADF()) =\n%s\nwant\n%s", result, markdown)
+	}
+}
@@ -2,11 +2,15 @@ package jira
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"got/pkg/config"
 	"io/ioutil"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 )
 
 type jiraAPIEndpoint string
@@ -15,6 +19,10 @@ const (
 	jiraRequestPathGetIssue    jiraAPIEndpoint = "issue/%s?expand=renderedFields"
 	jiraRequestPathCreateIssue jiraAPIEndpoint = "issue/"
 	jiraRequestPathUpdateIssue jiraAPIEndpoint = "issue/%s"
+	jiraRequestPathAddWorklog  jiraAPIEndpoint = "issue/%s/worklog"
+	jiraRequestPathSearch      jiraAPIEndpoint = "search"
+	jiraRequestPathGetProject  jiraAPIEndpoint = "project/%s"
+	jiraRequestPathRemoteLink  jiraAPIEndpoint = "issue/%s/remotelink"
 )
 
 type jiraOperation string
@@ -23,33 +31,69 @@ const (
 	jiraOperationGetIssue    jiraOperation = "getIssue"
 	jiraOperationCreateIssue jiraOperation = "createIssue"
 	jiraOperationUpdateIssue jiraOperation = "updateIssue"
+	jiraOperationAddWorklog  jiraOperation = "addWorklog"
+	jiraOperationSearch      jiraOperation = "search"
+	jiraOperationGetProject  jiraOperation = "getProject"
+	jiraOperationRemoteLink  jiraOperation = "remoteLink"
 )
 
-type issueTypes string
+// searchIssuesChunkSize limits number of issue keys requested by a single search request
+const searchIssuesChunkSize = 50
 
-const (
-	storyIssueType issueTypes = "Story"
-)
+// projectLabelsPageSize is a number of issues which labels are collected by a single search request
+const projectLabelsPageSize = 100
+
+// searchIssuesFields is a list of fields requested for issues found by search
+var searchIssuesFields = []string{"summary", "status", "issuetype", "assignee", "description", "labels", "updated"}
+
+// DefaultIssueType is a type of Jira issues created by the application
+const DefaultIssueType = "Story"
+
+// ErrOffline is returned for requests to Jira in offline mode
+var ErrOffline = errors.New("Jira is not available in offline mode")
 
-// GetIssue tries to find issue by project code from configuration and by code
-func GetIssue(issueKey string) (Issue, error) {
-	client := &http.Client{}
+// GetIssue returns issue by key.
+// Issues fetched within cache TTL are read from the local cache, stale issues are revalidated with ETag.
+func (client *Client) GetIssue(ctx context.Context, issueKey string) (Issue, error) {
+	cachedIssue, isCached := client.cache.Get(issueKey)
+	if isCached && (client.settings.Offline || cachedIssue.IsFresh(time.Now(), client.settings.CacheTTL)) {
+		return cachedIssue.Issue, nil
+	}
+	if client.settings.Offline {
+		return Issue{}, fmt.Errorf("Jira issue %s is not cached, run without -offline to fetch it", issueKey)
+	}
+
+	ctx, cancel := client.withRequestTimeout(ctx)
+	defer cancel()
 
-	requestURL, err := getRequestURL(jiraOperationGetIssue, issueKey)
+	requestURL, err := client.getRequestURL(jiraOperationGetIssue, issueKey)
 	if err != nil {
 		return Issue{}, err
 	}
 
-	req, err := http.NewRequest("GET", requestURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
 		return Issue{}, err
 	}
-	setJiraRequestHeaders(req)
+	if isCached && cachedIssue.ETag != "" {
+		req.Header.Set("If-None-Match", cachedIssue.ETag)
+	}
 
-	resp, err := client.Do(req)
+	resp, err := client.sendRequest(req)
 	if err != nil {
 		return Issue{}, err
 	}
+	defer resp.Body.Close()
+
+	if isCached && resp.StatusCode == http.StatusNotModified {
+		cachedIssue.FetchedAt = time.Now()
+		client.cache.Put(cachedIssue)
+		return cachedIssue.Issue, nil
+	}
+
+	if err := checkResponse(resp); err != nil {
+		return Issue{}, fmt.Errorf("Failed to get Jira issue %s: %w", issueKey, err)
+	}
 
 	bodyText, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -63,45 +107,199 @@ func GetIssue(issueKey string) (Issue, error) {
 	}
 
 	if issue.Key != issueKey {
-		return issue, fmt.Errorf("Jira ticket with key %s not found", issueKey)
+		return issue, fmt.Errorf("Jira returned issue %s instead of %s, the issue may have been moved", issue.Key, issueKey)
 	}
 
+	client.cache.Put(CachedIssue{Issue: issue, FetchedAt: time.Now(), ETag: resp.Header.Get("ETag")})
+
 	return issue, nil
 }
 
-// CreateIssue creates Jira issue with specified summary
-func CreateIssue(summary string) (string, error) {
-	client := &http.Client{}
+// GetIssues returns issues by keys using search requests for chunks of keys.
+// Keys of issues that do not exist or are not visible to the user are skipped.
+// Issues fetched within cache TTL are read from the local cache, in offline mode only cached issues are returned.
+func (client *Client) GetIssues(ctx context.Context, issueKeys []string) ([]Issue, error) {
+	var uniqueIssueKeys []string
+	for _, issueKey := range issueKeys {
+		if !stringInSlice(issueKey, uniqueIssueKeys) {
+			uniqueIssueKeys = append(uniqueIssueKeys, issueKey)
+		}
+	}
+
+	now := time.Now()
+
+	var issues []Issue
+	var issueKeysToFetch []string
+	for _, issueKey := range uniqueIssueKeys {
+		cachedIssue, isCached := client.cache.Get(issueKey)
+		if isCached && (client.settings.Offline || cachedIssue.IsFresh(now, client.settings.CacheTTL)) {
+			issues = append(issues, cachedIssue.Issue)
+			continue
+		}
+		if !client.settings.Offline {
+			issueKeysToFetch = append(issueKeysToFetch, issueKey)
+		}
+	}
+
+	for start := 0; start < len(issueKeysToFetch); start += searchIssuesChunkSize {
+		end := start + searchIssuesChunkSize
+		if end > len(issueKeysToFetch) {
+			end = len(issueKeysToFetch)
+		}
+
+		chunkIssues, err := client.searchIssuesByKeys(ctx, issueKeysToFetch[start:end])
+		if err != nil {
+			return issues, err
+		}
+		for _, issue := range chunkIssues {
+			client.cache.Put(CachedIssue{Issue: issue, FetchedAt: time.Now()})
+		}
+		issues = append(issues, chunkIssues...)
+	}
+
+	return issues, nil
+}
+
+func (client *Client) searchIssuesByKeys(ctx context.Context, issueKeys []string) ([]Issue, error) {
+	quotedIssueKeys := make([]string, len(issueKeys))
+	for i, issueKey := range issueKeys {
+		quotedIssueKeys[i] = quoteJQLValue(issueKey)
+	}
+
+	response, err := client.searchIssues(ctx, SearchIssuesData{
+		JQL:           fmt.Sprintf("key in (%s)", strings.Join(quotedIssueKeys, ", ")),
+		Fields:        searchIssuesFields,
+		Expand:        []string{"renderedFields"},
+		MaxResults:    len(issueKeys),
+		ValidateQuery: "warn",
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	requestURL, err := getRequestURL(jiraOperationCreateIssue, "")
+	return response.Issues, nil
+}
+
+// GetProjectLabels returns sorted labels of issues of the project
+func (client *Client) GetProjectLabels(ctx context.Context) ([]string, error) {
+	var labels []string
+	for startAt := 0; ; startAt += projectLabelsPageSize {
+		response, err := client.searchIssues(ctx, SearchIssuesData{
+			JQL:        fmt.Sprintf("project = %s AND labels is not EMPTY ORDER BY key", quoteJQLValue(client.settings.ProjectCode)),
+			Fields:     []string{"labels"},
+			StartAt:    startAt,
+			MaxResults: projectLabelsPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range response.Issues {
+			for _, label := range issue.Fields.Labels {
+				if !stringInSlice(label, labels) {
+					labels = append(labels, label)
+				}
+			}
+		}
+		if len(response.Issues) == 0 || startAt+len(response.Issues) >= response.Total {
+			break
+		}
+	}
+
+	sort.Strings(labels)
+	return labels, nil
+}
+
+func (client *Client) searchIssues(ctx context.Context, formValues SearchIssuesData) (SearchIssuesResponse, error) {
+	ctx, cancel := client.withRequestTimeout(withIdempotentRequest(ctx))
+	defer cancel()
+
+	requestURL, err := client.getRequestURL(jiraOperationSearch, "")
+	if err != nil {
+		return SearchIssuesResponse{}, err
+	}
+
+	formValuesByte, err := json.Marshal(formValues)
+	if err != nil {
+		return SearchIssuesResponse{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewReader(formValuesByte))
+	if err != nil {
+		return SearchIssuesResponse{}, fmt.Errorf("Failed to convert form values to json. Error: '%s'", err)
+	}
+
+	resp, err := client.sendRequest(req)
+	if err != nil {
+		return SearchIssuesResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return SearchIssuesResponse{}, fmt.Errorf("Failed to search Jira issues: %w", err)
+	}
+
+	bodyText, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return SearchIssuesResponse{}, fmt.Errorf("Failed to parse reponse body: %s", err.Error())
+	}
+
+	var response SearchIssuesResponse
+	err = json.Unmarshal(bodyText, &response)
+	if err != nil {
+		return SearchIssuesResponse{}, fmt.Errorf("Failed to parse search Jira issues response body: %s", err.Error())
+	}
+
+	return response, nil
+}
+
+// CreateIssue creates Jira issue in the configured project and returns its key
+func (client *Client) CreateIssue(ctx context.Context, newIssue NewIssue) (string, error) {
+	ctx, cancel := client.withRequestTimeout(ctx)
+	defer cancel()
+
+	requestURL, err := client.getRequestURL(jiraOperationCreateIssue, "")
 	if err != nil {
 		return "", err
 	}
 
+	issueType := newIssue.IssueType
+	if issueType == "" {
+		issueType = DefaultIssueType
+	}
 	formValues := CreateIssueData{
 		Fields: CreateIssueDataFields{
-			Project:   CreateIssueDataProject{Key: config.Options.Jira.ProjectCode},
-			IssueType: CreateIssueDataIssueType{Name: string(storyIssueType)},
-			Summary:   summary,
+			Project:     CreateIssueDataProject{Key: client.settings.ProjectCode},
+			IssueType:   CreateIssueDataIssueType{Name: issueType},
+			Summary:     newIssue.Summary,
+			Description: newIssue.Description,
+			Labels:      newIssue.Labels,
 		},
 	}
+	if newIssue.Priority != "" {
+		formValues.Fields.Priority = &CreateIssueDataPriority{Name: newIssue.Priority}
+	}
+	for _, component := range newIssue.Components {
+		formValues.Fields.Components = append(formValues.Fields.Components, IssueComponent{Name: component})
+	}
 	formValuesByte, err := json.Marshal(formValues)
 	if err != nil {
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", requestURL, bytes.NewReader(formValuesByte))
+	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewReader(formValuesByte))
 	if err != nil {
 		return "", fmt.Errorf("Failed to convert form values to json: %s", err)
 	}
 
-	setJiraRequestHeaders(req)
-	resp, err := client.Do(req)
+	resp, err := client.sendRequest(req)
 	if err != nil {
 		return "", err
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("Failed to create Jira ticket. Status code: %d", resp.StatusCode)
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return "", fmt.Errorf("Failed to create Jira ticket: %w", err)
 	}
 
 	bodyText, err := ioutil.ReadAll(resp.Body)
@@ -112,60 +310,110 @@ func CreateIssue(summary string) (string, error) {
 	var response CreateIssueResponse
 	err = json.Unmarshal([]byte(bodyText), &response)
 	if err != nil {
-		return "", fmt.Errorf("Jira ticket '%s' was not created: %s\n%s", summary, err, string(bodyText))
+		return "", fmt.Errorf("Jira ticket '%s' was not created: %s\n%s", newIssue.Summary, err, string(bodyText))
 	}
 
-	fmt.Println(response)
-
 	return response.Key, nil
 }
 
-// AddIssueLabels updates Jira issue summary
-func AddIssueLabels(issueKey string, labels []string) ([]string, error) {
-	client := &http.Client{}
+// GetProject returns the configured Jira project with its issue types and components
+func (client *Client) GetProject(ctx context.Context) (Project, error) {
+	ctx, cancel := client.withRequestTimeout(ctx)
+	defer cancel()
 
-	requestURL, err := getRequestURL(jiraOperationUpdateIssue, issueKey)
+	requestURL, err := client.getRequestURL(jiraOperationGetProject, client.settings.ProjectCode)
 	if err != nil {
-		return labels, err
+		return Project{}, err
 	}
 
-	var updateIssueLabels []UpdateIssueLabels
-	for _, label := range labels {
-		updateIssueLabels = append(updateIssueLabels, UpdateIssueLabels{Add: label}) // note the = instead of :=
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return Project{}, err
 	}
-	formValues := UpdateIssueData{
-		Update: UpdateIssueDataFields{
-			Labels: updateIssueLabels,
-		},
+
+	resp, err := client.sendRequest(req)
+	if err != nil {
+		return Project{}, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return Project{}, fmt.Errorf("Failed to get Jira project %s: %w", client.settings.ProjectCode, err)
+	}
+
+	bodyText, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Project{}, fmt.Errorf("Failed to parse reponse body: %s", err.Error())
+	}
+
+	var project Project
+	err = json.Unmarshal(bodyText, &project)
+	if err != nil {
+		return Project{}, fmt.Errorf("Failed to parse Jira project %s: %s", client.settings.ProjectCode, err.Error())
+	}
+
+	return project, nil
+}
+
+// AddIssueLabels adds labels to Jira issue
+func (client *Client) AddIssueLabels(ctx context.Context, issueKey string, labels []string) ([]string, error) {
+	return labels, client.UpdateIssue(ctx, issueKey, IssueUpdate{Labels: labels})
+}
+
+// UpdateIssue adds and removes labels, adds components to Jira issue and sets its fields
+func (client *Client) UpdateIssue(ctx context.Context, issueKey string, update IssueUpdate) error {
+	ctx, cancel := client.withRequestTimeout(ctx)
+	defer cancel()
+
+	requestURL, err := client.getRequestURL(jiraOperationUpdateIssue, issueKey)
+	if err != nil {
+		return err
+	}
+
+	formValues := UpdateIssueData{Fields: update.Fields}
+	if len(update.SetLabels) > 0 {
+		formValues.Update.Labels = append(formValues.Update.Labels, UpdateIssueLabels{Set: update.SetLabels})
+	}
+	for _, label := range update.Labels {
+		formValues.Update.Labels = append(formValues.Update.Labels, UpdateIssueLabels{Add: label})
+	}
+	for _, label := range update.RemoveLabels {
+		formValues.Update.Labels = append(formValues.Update.Labels, UpdateIssueLabels{Remove: label})
+	}
+	for _, component := range update.Components {
+		formValues.Update.Components = append(formValues.Update.Components, UpdateIssueComponents{Add: IssueComponent{Name: component}})
 	}
 	formValuesByte, err := json.Marshal(formValues)
 	if err != nil {
-		return labels, err
+		return err
 	}
 
-	req, err := http.NewRequest("PUT", requestURL, bytes.NewReader(formValuesByte))
+	req, err := http.NewRequestWithContext(ctx, "PUT", requestURL, bytes.NewReader(formValuesByte))
 	if err != nil {
-		return labels, fmt.Errorf("Failed to convert form values to json. Error: '%s'", err)
+		return fmt.Errorf("Failed to convert form values to json. Error: '%s'", err)
 	}
 
-	setJiraRequestHeaders(req)
-	resp, err := client.Do(req)
+	resp, err := client.sendRequest(req)
 	if err != nil {
-		return labels, err
+		return err
 	}
+	defer resp.Body.Close()
 
-	if resp.StatusCode != 204 {
-		return labels, fmt.Errorf("Failed to update Jira ticket. Status code: %d", resp.StatusCode)
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("Failed to update Jira ticket %s: %w", issueKey, err)
 	}
 
-	return labels, nil
+	client.cache.Remove(issueKey)
+
+	return nil
 }
 
 // UpdateIssueSummary updates Jira issue summary
-func UpdateIssueSummary(issueKey string, summary string) (string, error) {
-	client := &http.Client{}
+func (client *Client) UpdateIssueSummary(ctx context.Context, issueKey string, summary string) (string, error) {
+	ctx, cancel := client.withRequestTimeout(ctx)
+	defer cancel()
 
-	requestURL, err := getRequestURL(jiraOperationUpdateIssue, issueKey)
+	requestURL, err := client.getRequestURL(jiraOperationUpdateIssue, issueKey)
 	if err != nil {
 		return "", err
 	}
@@ -182,40 +430,167 @@ func UpdateIssueSummary(issueKey string, summary string) (string, error) {
 		return "", err
 	}
 
-	req, err := http.NewRequest("PUT", requestURL, bytes.NewReader(formValuesByte))
+	req, err := http.NewRequestWithContext(ctx, "PUT", requestURL, bytes.NewReader(formValuesByte))
 	if err != nil {
 		return "", fmt.Errorf("Failed to convert form values to json. Error: '%s'", err)
 	}
 
-	setJiraRequestHeaders(req)
-	resp, err := client.Do(req)
+	resp, err := client.sendRequest(req)
 	if err != nil {
 		return "", err
 	}
+	defer resp.Body.Close()
 
-	if resp.StatusCode != 204 {
-		return "", fmt.Errorf("Failed to update Jira ticket. Status code: %d", resp.StatusCode)
+	if err := checkResponse(resp); err != nil {
+		return "", fmt.Errorf("Failed to update Jira ticket %s summary: %w", issueKey, err)
 	}
 
+	client.cache.Remove(issueKey)
+
 	return summary, nil
 }
 
-func getRequestURL(operation jiraOperation, issueKey string) (string, error) {
+// AddIssueWorklog logs time spent on Jira issue starting from specified time
+func (client *Client) AddIssueWorklog(ctx context.Context, issueKey string, started time.Time, timeSpent time.Duration) error {
+	ctx, cancel := client.withRequestTimeout(ctx)
+	defer cancel()
+
+	requestURL, err := client.getRequestURL(jiraOperationAddWorklog, issueKey)
+	if err != nil {
+		return err
+	}
+
+	formValues := AddWorklogData{
+		Started:          started.Format(worklogTimeLayout),
+		TimeSpentSeconds: int(timeSpent.Seconds()),
+	}
+	formValuesByte, err := json.Marshal(formValues)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewReader(formValuesByte))
+	if err != nil {
+		return fmt.Errorf("Failed to convert form values to json. Error: '%s'", err)
+	}
+
+	resp, err := client.sendRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("Failed to add worklog to Jira ticket %s: %w", issueKey, err)
+	}
+
+	return nil
+}
+
+// SetIssueRemoteLink creates remote link of Jira issue or updates the link with the same global id
+func (client *Client) SetIssueRemoteLink(ctx context.Context, issueKey string, remoteLink RemoteLink) error {
+	ctx, cancel := client.withRequestTimeout(ctx)
+	defer cancel()
+
+	requestURL, err := client.getRequestURL(jiraOperationRemoteLink, issueKey)
+	if err != nil {
+		return err
+	}
+
+	formValuesByte, err := json.Marshal(remoteLink)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewReader(formValuesByte))
+	if err != nil {
+		return fmt.Errorf("Failed to convert form values to json. Error: '%s'", err)
+	}
+
+	resp, err := client.sendRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("Failed to add remote link to Jira ticket %s: %w", issueKey, err)
+	}
+
+	return nil
+}
+
+// withRequestTimeout limits duration of Jira request by configured timeout
+func (client *Client) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if client.settings.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, client.settings.Timeout)
+}
+
+func (client *Client) getRequestURL(operation jiraOperation, issueKey string) (string, error) {
 	switch operation {
 	case jiraOperationGetIssue:
 		formattedPath := fmt.Sprintf(string(jiraRequestPathGetIssue), issueKey)
-		return fmt.Sprintf("%s/%s", config.Options.Jira.APIEndPoint, formattedPath), nil
+		return fmt.Sprintf("%s/%s", client.settings.APIEndpoint, formattedPath), nil
 	case jiraOperationCreateIssue:
-		return fmt.Sprintf("%s/%s", config.Options.Jira.APIEndPoint, jiraRequestPathCreateIssue), nil
+		return fmt.Sprintf("%s/%s", client.settings.APIEndpoint, jiraRequestPathCreateIssue), nil
 	case jiraOperationUpdateIssue:
 		formattedPath := fmt.Sprintf(string(jiraRequestPathUpdateIssue), issueKey)
-		return fmt.Sprintf("%s/%s", config.Options.Jira.APIEndPoint, formattedPath), nil
+		return fmt.Sprintf("%s/%s", client.settings.APIEndpoint, formattedPath), nil
+	case jiraOperationSearch:
+		return fmt.Sprintf("%s/%s", client.settings.APIEndpoint, jiraRequestPathSearch), nil
+	case jiraOperationAddWorklog:
+		formattedPath := fmt.Sprintf(string(jiraRequestPathAddWorklog), issueKey)
+		return fmt.Sprintf("%s/%s", client.settings.APIEndpoint, formattedPath), nil
+	case jiraOperationGetProject:
+		formattedPath := fmt.Sprintf(string(jiraRequestPathGetProject), issueKey)
+		return fmt.Sprintf("%s/%s", client.settings.APIEndpoint, formattedPath), nil
+	case jiraOperationRemoteLink:
+		formattedPath := fmt.Sprintf(string(jiraRequestPathRemoteLink), issueKey)
+		return fmt.Sprintf("%s/%s", client.settings.APIEndpoint, formattedPath), nil
 	default:
 		return "", fmt.Errorf("Invalid jira operation '%s'", operation)
 	}
 }
 
-func setJiraRequestHeaders(req *http.Request) {
+// sendRequest sends authenticated Jira request retrying failures caused by rate limits and temporary errors
+func (client *Client) sendRequest(req *http.Request) (*http.Response, error) {
+	if client.settings.Offline {
+		return nil, ErrOffline
+	}
+
+	client.setJiraRequestHeaders(req)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		resp.Body.Close()
+		retryAfter, _ := getRetryDelay(resp, time.Now())
+		return nil, RateLimitError{RetryAfter: retryAfter}
+	}
+
+	return resp, nil
+}
+
+func (client *Client) setJiraRequestHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(config.Options.Jira.Email, config.Options.Jira.APIKey)
+	req.SetBasicAuth(client.settings.Email, client.settings.APIKey)
+}
+
+func quoteJQLValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+func stringInSlice(a string, list []string) bool {
+	for _, b := range list {
+		if b == a {
+			return true
+		}
+	}
+	return false
 }
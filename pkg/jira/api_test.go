@@ -0,0 +1,427 @@
+package jira_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"got/pkg/jira"
+	"got/pkg/jira/jiratest"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupServer starts fake Jira server and returns settings of client using it
+func setupServer(t *testing.T) (*jiratest.Server, jira.Settings) {
+	server := jiratest.NewServer("PC")
+	server.RequireCredentials("user@example.com", "secret")
+	t.Cleanup(server.Close)
+
+	settings := jira.Settings{
+		APIEndpoint:    server.APIEndpoint(),
+		Email:          "user@example.com",
+		APIKey:         "secret",
+		ProjectCode:    "PC",
+		Timeout:        10 * time.Second,
+		MaxRetries:     2,
+		CacheDirectory: t.TempDir(),
+	}
+
+	return server, settings
+}
+
+func TestGetIssue(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+	server.AddIssue(jiratest.Issue{
+		Summary:        "Fix login",
+		Description:    "Users can not log in",
+		IssueType:      "Bug",
+		Status:         "In Progress",
+		StatusCategory: jiratest.StatusCategoryInProgress,
+		Assignee:       "Jane Doe",
+	})
+
+	issue, err := client.GetIssue(context.Background(), "PC-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Key != "PC-1" || issue.Fields.Summary != "Fix login" || issue.Fields.IssueType.Name != "Bug" {
+		t.Errorf("GetIssue() = %+v, want PC-1 bug 'Fix login'", issue)
+	}
+	if !issue.IsInProgress() || issue.GetAssigneeName() != "Jane Doe" {
+		t.Errorf("GetIssue() status = %+v, assignee = %q", issue.Fields.Status, issue.GetAssigneeName())
+	}
+	if issue.GetStrippedDescription() != "Users can not log in" {
+		t.Errorf("GetStrippedDescription() = %q, want %q", issue.GetStrippedDescription(), "Users can not log in")
+	}
+}
+
+func TestGetIssueErrors(t *testing.T) {
+	_, settings := setupServer(t)
+	client := jira.NewClient(settings)
+
+	_, err := client.GetIssue(context.Background(), "PC-404")
+	var jiraError *jira.Error
+	if !errors.As(err, &jiraError) || jiraError.StatusCode != http.StatusNotFound {
+		t.Errorf("GetIssue() of missing issue = %v, want 404 error", err)
+	}
+
+	settings.APIKey = "other"
+	client = jira.NewClient(settings)
+	_, err = client.GetIssue(context.Background(), "PC-1")
+	if !errors.As(err, &jiraError) || jiraError.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GetIssue() with bad credentials = %v, want 401 error", err)
+	}
+	if !strings.Contains(err.Error(), "bad credentials") {
+		t.Errorf("GetIssue() error = %q, want bad credentials message", err)
+	}
+}
+
+func TestGetIssueRetriesRateLimitedRequests(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+	server.AddIssue(jiratest.Issue{Summary: "Rate limited"})
+	server.RespondWithError(http.StatusTooManyRequests)
+	server.RespondWithError(http.StatusServiceUnavailable)
+
+	issue, err := client.GetIssue(context.Background(), "PC-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Fields.Summary != "Rate limited" || len(server.Requests()) != 3 {
+		t.Errorf("GetIssue() = %q after %d requests, want issue after 3 requests", issue.Fields.Summary, len(server.Requests()))
+	}
+
+	server.RespondWithError(http.StatusTooManyRequests)
+	server.RespondWithError(http.StatusTooManyRequests)
+	server.RespondWithError(http.StatusTooManyRequests)
+	_, err = client.GetIssue(context.Background(), "PC-1")
+	var rateLimitError jira.RateLimitError
+	if !errors.As(err, &rateLimitError) {
+		t.Errorf("GetIssue() = %v, want rate limit error when retries are exhausted", err)
+	}
+}
+
+func TestGetIssueUsesCache(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+	server.AddIssue(jiratest.Issue{Summary: "Cached"})
+	settings.CacheTTL = time.Hour
+	client = jira.NewClient(settings)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetIssue(context.Background(), "PC-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(server.Requests()) != 1 {
+		t.Errorf("GetIssue() sent %d requests for fresh cached issue, want 1", len(server.Requests()))
+	}
+
+	settings.CacheTTL = 0
+	client = jira.NewClient(settings)
+	server.ResetRequests()
+	issue, err := client.GetIssue(context.Background(), "PC-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Fields.Summary != "Cached" || len(server.Requests()) != 1 {
+		t.Fatalf("GetIssue() of stale issue = %q after %d requests", issue.Fields.Summary, len(server.Requests()))
+	}
+	if ifNoneMatch := server.Requests()[0].Header.Get("If-None-Match"); ifNoneMatch == "" {
+		t.Error("GetIssue() did not revalidate stale issue with ETag")
+	}
+
+	settings.Offline = true
+	client = jira.NewClient(settings)
+	server.ResetRequests()
+	if _, err := client.GetIssue(context.Background(), "PC-1"); err != nil {
+		t.Errorf("GetIssue() of cached issue in offline mode = %s", err)
+	}
+	if _, err := client.GetIssue(context.Background(), "PC-2"); err == nil {
+		t.Error("GetIssue() of not cached issue in offline mode returned no error")
+	}
+	if len(server.Requests()) != 0 {
+		t.Errorf("GetIssue() in offline mode sent %d requests", len(server.Requests()))
+	}
+}
+
+func TestGetIssues(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+
+	var issueKeys []string
+	for i := 1; i <= 60; i++ {
+		issue := server.AddIssue(jiratest.Issue{Summary: fmt.Sprintf("Issue %d", i)})
+		issueKeys = append(issueKeys, issue.Key)
+	}
+	issueKeys = append(issueKeys, "PC-1", "PC-999")
+
+	issues, err := client.GetIssues(context.Background(), issueKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 60 {
+		t.Errorf("GetIssues() returned %d issues, want 60", len(issues))
+	}
+	if len(server.Requests()) != 2 {
+		t.Errorf("GetIssues() sent %d search requests, want 2", len(server.Requests()))
+	}
+}
+
+func TestCreateIssue(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+
+	issueKey, err := client.CreateIssue(context.Background(), jira.NewIssue{Summary: "New feature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue, ok := server.Issue(issueKey)
+	if !ok || issue.Summary != "New feature" || issue.IssueType != jira.DefaultIssueType {
+		t.Errorf("CreateIssue() stored %+v, want story 'New feature'", issue)
+	}
+	if strings.Contains(server.Requests()[0].Body, "description") {
+		t.Errorf("CreateIssue() request = %s, want no description", server.Requests()[0].Body)
+	}
+
+	_, err = client.CreateIssue(context.Background(), jira.NewIssue{Summary: " "})
+	if err == nil || !strings.Contains(err.Error(), "summary: You must specify a summary of the issue.") {
+		t.Errorf("CreateIssue() with empty summary = %v, want summary field error", err)
+	}
+
+	_, err = client.CreateIssue(context.Background(), jira.NewIssue{Summary: "Feature", IssueType: "Feature"})
+	if err == nil || !strings.Contains(err.Error(), "issuetype: Specify a valid issue type") {
+		t.Errorf("CreateIssue() with unknown issue type = %v, want issue type field error", err)
+	}
+}
+
+func TestCreateIssueWithDescription(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+
+	description := jira.MarkdownToADF("Steps:\n\n1. Log in\n2. Log out")
+	issueKey, err := client.CreateIssue(context.Background(), jira.NewIssue{
+		Summary:     "Fix logout",
+		IssueType:   "Bug",
+		Description: &description,
+		Components:  []string{"API"},
+		Labels:      []string{"needs-triage"},
+		Priority:    "High",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue, _ := server.Issue(issueKey)
+	if issue.IssueType != "Bug" || issue.DescriptionADF == nil || !strings.Contains(issue.Description, "Log in") {
+		t.Errorf("CreateIssue() stored %s with description %q, want bug with steps", issue.IssueType, issue.Description)
+	}
+	if len(issue.Components) != 1 || issue.Components[0] != "API" {
+		t.Errorf("CreateIssue() stored components %v, want [API]", issue.Components)
+	}
+	priority, _ := issue.Fields["priority"].(map[string]interface{})
+	if len(issue.Labels) != 1 || issue.Labels[0] != "needs-triage" || priority["name"] != "High" {
+		t.Errorf("CreateIssue() stored labels %v and priority %v, want needs-triage and High", issue.Labels, priority)
+	}
+}
+
+func TestGetProject(t *testing.T) {
+	server, settings := setupServer(t)
+	server.IssueTypes = []string{"Story", "Bug"}
+	server.Components = []string{"API", "Billing"}
+	client := jira.NewClient(settings)
+
+	project, err := client.GetProject(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Key != "PC" || len(project.IssueTypes) != 2 || project.IssueTypes[1].Name != "Bug" {
+		t.Errorf("GetProject() = %+v, want PC project with Story and Bug issue types", project)
+	}
+	if len(project.Components) != 2 || project.Components[0].Name != "API" {
+		t.Errorf("GetProject() components = %+v, want API and Billing", project.Components)
+	}
+
+	settings.ProjectCode = "OTHER"
+	_, err = jira.NewClient(settings).GetProject(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Failed to get Jira project OTHER") {
+		t.Errorf("GetProject() for unknown project = %v, want error", err)
+	}
+}
+
+func TestUpdateIssue(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+	server.AddIssue(jiratest.Issue{Summary: "Old summary", Labels: []string{"backend"}})
+
+	_, err := client.AddIssueLabels(context.Background(), "PC-1", []string{"repo-got", "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.UpdateIssueSummary(context.Background(), "PC-1", "New summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue, _ := server.Issue("PC-1")
+	if issue.Summary != "New summary" || strings.Join(issue.Labels, ",") != "backend,repo-got" {
+		t.Errorf("updated issue = %+v, want new summary and labels backend,repo-got", issue)
+	}
+
+	_, err = client.AddIssueLabels(context.Background(), "PC-1", []string{"with space"})
+	if err == nil || !strings.Contains(err.Error(), "contains spaces") {
+		t.Errorf("AddIssueLabels() with invalid label = %v, want labels field error", err)
+	}
+}
+
+func TestUpdateIssueComponentsAndFields(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+	server.AddIssue(jiratest.Issue{Summary: "Components", Components: []string{"API"}})
+
+	err := client.UpdateIssue(context.Background(), "PC-1", jira.IssueUpdate{
+		Labels:     []string{"team-payments"},
+		Components: []string{"API", "Billing"},
+		Fields:     map[string]interface{}{"customfield_10001": map[string]string{"value": "Payments"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue, _ := server.Issue("PC-1")
+	if strings.Join(issue.Labels, ",") != "team-payments" || strings.Join(issue.Components, ",") != "API,Billing" {
+		t.Errorf("updated issue = %+v, want label team-payments and components API,Billing", issue)
+	}
+	if team, _ := issue.Fields["customfield_10001"].(map[string]interface{}); team["value"] != "Payments" {
+		t.Errorf("updated Team field = %+v, want Payments", issue.Fields["customfield_10001"])
+	}
+}
+
+func TestRemoveIssueLabelsAndGetProjectLabels(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+	server.AddIssue(jiratest.Issue{Summary: "First", Labels: []string{"backend", "repo-got"}})
+	server.AddIssue(jiratest.Issue{Summary: "Second", Labels: []string{"frontend", "backend"}})
+	server.AddIssue(jiratest.Issue{Summary: "Without labels"})
+	server.AddIssue(jiratest.Issue{Key: "OTHER-1", Summary: "Other project", Labels: []string{"other"}})
+
+	err := client.UpdateIssue(context.Background(), "PC-1", jira.IssueUpdate{RemoveLabels: []string{"repo-got"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue, _ := server.Issue("PC-1")
+	if strings.Join(issue.Labels, ",") != "backend" {
+		t.Errorf("labels after removal = %+v, want backend", issue.Labels)
+	}
+
+	labels, err := client.GetProjectLabels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(labels, ",") != "backend,frontend" {
+		t.Errorf("GetProjectLabels() = %+v, want backend,frontend", labels)
+	}
+}
+
+func TestSetIssueLabels(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+	server.AddIssue(jiratest.Issue{Summary: "Labels", Labels: []string{"backend", "repo-got"}})
+
+	update := jira.IssueUpdate{SetLabels: []string{"frontend", "api"}, Labels: []string{"got"}}
+	if err := client.UpdateIssue(context.Background(), "PC-1", update); err != nil {
+		t.Fatal(err)
+	}
+
+	issue, _ := server.Issue("PC-1")
+	if strings.Join(issue.Labels, ",") != "frontend,api,got" {
+		t.Errorf("labels after set = %+v, want frontend,api,got", issue.Labels)
+	}
+	if body := server.Requests()[0].Body; !strings.Contains(body, `"update":{"labels":[{"set":["frontend","api"]},{"add":"got"}]}`) {
+		t.Errorf("update request body = %s, want labels set with the update verb", body)
+	}
+}
+
+func TestGetProjectLabels_Pages(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+	var labels []string
+	for i := 0; i < 150; i++ {
+		label := fmt.Sprintf("label-%03d", i)
+		labels = append(labels, label)
+		server.AddIssue(jiratest.Issue{Summary: "Labeled", Labels: []string{label}})
+	}
+	server.AddIssue(jiratest.Issue{Key: "OTHER-1", Summary: "Other project", Labels: []string{"other"}})
+
+	foundLabels, err := client.GetProjectLabels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(foundLabels, labels) {
+		t.Errorf("GetProjectLabels() returned %d labels, want %d", len(foundLabels), len(labels))
+	}
+	if requests := server.Requests(); len(requests) != 2 {
+		t.Errorf("GetProjectLabels() sent %d requests, want 2 pages", len(requests))
+	}
+}
+
+func TestAddIssueWorklog(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+	server.AddIssue(jiratest.Issue{Summary: "Worklog"})
+
+	started := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+	err := client.AddIssueWorklog(context.Background(), "PC-1", started, 90*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue, _ := server.Issue("PC-1")
+	if len(issue.Worklogs) != 1 || issue.Worklogs[0].TimeSpentSeconds != 5400 {
+		t.Errorf("worklogs = %+v, want 1h 30m worklog", issue.Worklogs)
+	}
+	if issue.Worklogs[0].Started != "2021-03-01T09:00:00.000+0000" {
+		t.Errorf("worklog started = %q", issue.Worklogs[0].Started)
+	}
+}
+
+func TestSetIssueRemoteLink(t *testing.T) {
+	server, settings := setupServer(t)
+	client := jira.NewClient(settings)
+	server.AddIssue(jiratest.Issue{Summary: "Remote link"})
+
+	link := jira.RemoteLink{
+		GlobalID:     "got:branch:https://github.com/octo/got/tree/PC-1/remote_link",
+		Relationship: "branch",
+		Object:       jira.RemoteLinkObject{URL: "https://github.com/octo/got/tree/PC-1/remote_link", Title: "PC-1/remote_link"},
+	}
+	ctx := context.Background()
+	if err := client.SetIssueRemoteLink(ctx, "PC-1", link); err != nil {
+		t.Fatal(err)
+	}
+	link.Object.Summary = "Pushed to origin"
+	if err := client.SetIssueRemoteLink(ctx, "PC-1", link); err != nil {
+		t.Fatal(err)
+	}
+
+	issue, _ := server.Issue("PC-1")
+	if len(issue.RemoteLinks) != 1 || issue.RemoteLinks[0].Summary != "Pushed to origin" {
+		t.Errorf("remote links = %+v, want the link updated", issue.RemoteLinks)
+	}
+
+	if err := client.SetIssueRemoteLink(ctx, "PC-2", link); err == nil {
+		t.Error("SetIssueRemoteLink for missing issue returned no error")
+	}
+}
+
+func TestGetIssueURL(t *testing.T) {
+	client := jira.NewClient(jira.Settings{APIEndpoint: "https://example.atlassian.net/rest/api/3"})
+	if issueURL := client.GetIssueURL("PC-1"); issueURL != "https://example.atlassian.net/browse/PC-1" {
+		t.Errorf("GetIssueURL returned %s, want https://example.atlassian.net/browse/PC-1", issueURL)
+	}
+}
@@ -0,0 +1,130 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CachedIssue is a Jira issue stored in the local cache
+type CachedIssue struct {
+	Issue     Issue     `json:"issue"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	ETag      string    `json:"etag,omitempty"`
+}
+
+// IsFresh reports whether the issue was fetched less than ttl ago
+func (cachedIssue CachedIssue) IsFresh(now time.Time, ttl time.Duration) bool {
+	return now.Sub(cachedIssue.FetchedAt) < ttl
+}
+
+// Cache stores fetched Jira issues in a local directory
+type Cache struct {
+	Directory string
+}
+
+// DefaultCache returns cache stored in the user cache directory
+func DefaultCache() (Cache, error) {
+	cacheDirectory := os.Getenv("XDG_CACHE_HOME")
+	if cacheDirectory == "" {
+		homeDirectory, err := os.UserHomeDir()
+		if err != nil {
+			return Cache{}, fmt.Errorf("Failed to find user home directory: %s", err.Error())
+		}
+		cacheDirectory = filepath.Join(homeDirectory, ".cache")
+	}
+
+	return Cache{Directory: filepath.Join(cacheDirectory, "got")}, nil
+}
+
+// Get returns cached issue by key
+func (cache Cache) Get(issueKey string) (CachedIssue, bool) {
+	if cache.Directory == "" {
+		return CachedIssue{}, false
+	}
+
+	content, err := ioutil.ReadFile(cache.getIssueFilePath(issueKey))
+	if err != nil {
+		return CachedIssue{}, false
+	}
+
+	var cachedIssue CachedIssue
+	if err := json.Unmarshal(content, &cachedIssue); err != nil || cachedIssue.Issue.Key != issueKey {
+		return CachedIssue{}, false
+	}
+
+	return cachedIssue, true
+}
+
+// Put stores issue in the cache replacing previously cached version
+func (cache Cache) Put(cachedIssue CachedIssue) error {
+	if cache.Directory == "" {
+		return nil
+	}
+
+	content, err := json.Marshal(cachedIssue)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(cache.Directory, 0755)
+	if err != nil {
+		return fmt.Errorf("Failed to create cache directory '%s': %s", cache.Directory, err.Error())
+	}
+
+	file, err := ioutil.TempFile(cache.Directory, cachedIssue.Issue.Key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("Failed to create cache file: %s", err.Error())
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to write cache file '%s': %s", file.Name(), err.Error())
+	}
+
+	return os.Rename(file.Name(), cache.getIssueFilePath(cachedIssue.Issue.Key))
+}
+
+// Remove deletes cached issue so the next read fetches it from Jira
+func (cache Cache) Remove(issueKey string) error {
+	if cache.Directory == "" {
+		return nil
+	}
+
+	err := os.Remove(cache.getIssueFilePath(issueKey))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Clear deletes all cached data
+func (cache Cache) Clear() error {
+	if cache.Directory == "" {
+		return nil
+	}
+	return os.RemoveAll(cache.Directory)
+}
+
+func (cache Cache) getIssueFilePath(issueKey string) string {
+	return filepath.Join(cache.Directory, filepath.Base(issueKey)+".json")
+}
+
+// getIssuesCache returns cache of issues of Jira instance with the API endpoint
+func (cache Cache) getIssuesCache(apiEndpoint string) Cache {
+	host := "default"
+	if endpointURL, err := url.Parse(apiEndpoint); err == nil && endpointURL.Host != "" {
+		host = endpointURL.Host
+	}
+
+	return Cache{Directory: filepath.Join(cache.Directory, "issues", host)}
+}
@@ -0,0 +1,68 @@
+package jira
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	cache := Cache{Directory: filepath.Join(t.TempDir(), "issues")}
+	if _, ok := cache.Get("PC-1"); ok {
+		t.Fatal("Get() of empty cache returned issue")
+	}
+
+	var issue Issue
+	issue.Key = "PC-1"
+	issue.Fields.Summary = "Cached issue"
+	fetchedAt := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	err := cache.Put(CachedIssue{Issue: issue, FetchedAt: fetchedAt, ETag: `"abc"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cachedIssue, ok := cache.Get("PC-1")
+	if !ok {
+		t.Fatal("Get() did not return stored issue")
+	}
+	if cachedIssue.Issue.Fields.Summary != "Cached issue" || cachedIssue.ETag != `"abc"` || !cachedIssue.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("Get() = %+v, want stored issue", cachedIssue)
+	}
+	if _, ok := cache.Get("PC-2"); ok {
+		t.Error("Get() returned issue that was not stored")
+	}
+
+	if err := cache.Remove("PC-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get("PC-1"); ok {
+		t.Error("Get() returned removed issue")
+	}
+	if err := cache.Remove("PC-1"); err != nil {
+		t.Errorf("Remove() of missing issue = %s, want nil", err)
+	}
+
+	cache.Put(CachedIssue{Issue: issue, FetchedAt: fetchedAt})
+	if err := cache.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cache.Directory); !os.IsNotExist(err) {
+		t.Errorf("Clear() left cache directory, stat error: %v", err)
+	}
+}
+
+func TestCachedIssueIsFresh(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	cachedIssue := CachedIssue{FetchedAt: now.Add(-time.Minute)}
+
+	if !cachedIssue.IsFresh(now, 5*time.Minute) {
+		t.Error("IsFresh() = false for issue fetched within TTL")
+	}
+	if cachedIssue.IsFresh(now, 30*time.Second) {
+		t.Error("IsFresh() = true for issue fetched before TTL")
+	}
+	if cachedIssue.IsFresh(now, 0) {
+		t.Error("IsFresh() = true with disabled TTL")
+	}
+}
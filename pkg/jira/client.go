@@ -0,0 +1,55 @@
+package jira
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Settings is a configuration of Jira client
+type Settings struct {
+	APIEndpoint string
+	Email       string
+	APIKey      string
+	// ProjectCode is a code of the project new issues are created in
+	ProjectCode string
+	// Timeout limits duration of every request including retries, zero disables the timeout
+	Timeout time.Duration
+	// MaxRetries is a number of retries of requests failed because of rate limits or temporary errors
+	MaxRetries int
+	// CacheDirectory is a directory of cached issues, empty value disables the cache
+	CacheDirectory string
+	// CacheTTL is a time during which cached issues are used without requests to Jira
+	CacheTTL time.Duration
+	// Offline makes client read issues only from the cache and fail other requests
+	Offline bool
+}
+
+// Client sends requests to Jira REST API
+type Client struct {
+	settings   Settings
+	httpClient *http.Client
+	cache      Cache
+}
+
+// NewClient returns Jira client with the settings
+func NewClient(settings Settings) *Client {
+	client := &Client{
+		settings:   settings,
+		httpClient: &http.Client{Transport: newRetryTransport(http.DefaultTransport, settings.MaxRetries)},
+	}
+	if settings.CacheDirectory != "" {
+		client.cache = Cache{Directory: settings.CacheDirectory}.getIssuesCache(settings.APIEndpoint)
+	}
+
+	return client
+}
+
+// GetIssueURL returns URL of the issue page in Jira web interface, e.g. 'https://example.atlassian.net/browse/PC-1'
+func (client *Client) GetIssueURL(issueKey string) string {
+	baseURL := client.settings.APIEndpoint
+	if index := strings.Index(baseURL, "/rest/api/"); index >= 0 {
+		baseURL = baseURL[:index]
+	}
+	return strings.TrimRight(baseURL, "/") + "/browse/" + issueKey
+}
@@ -0,0 +1,76 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Error is an error response of Jira API with messages decoded from the response body
+type Error struct {
+	StatusCode    int               `json:"-"`
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+func (err *Error) Error() string {
+	message := fmt.Sprintf("%d %s", err.StatusCode, getStatusDescription(err.StatusCode))
+
+	details := err.Details()
+	if len(details) == 0 {
+		return message
+	}
+	return fmt.Sprintf("%s: %s", message, strings.Join(details, "; "))
+}
+
+// Details returns general error messages followed by field errors sorted by field name
+func (err *Error) Details() []string {
+	details := append([]string{}, err.ErrorMessages...)
+
+	fields := make([]string, 0, len(err.Errors))
+	for field := range err.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		details = append(details, fmt.Sprintf("%s: %s", field, err.Errors[field]))
+	}
+
+	return details
+}
+
+func getStatusDescription(statusCode int) string {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return "bad credentials, check Jira email and API key"
+	case http.StatusForbidden:
+		return "access denied"
+	case http.StatusNotFound:
+		return "not found"
+	}
+	return strings.ToLower(http.StatusText(statusCode))
+}
+
+// checkResponse returns Error decoded from the response body when Jira responds with non-successful status code
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	jiraError := &Error{StatusCode: resp.StatusCode}
+	bodyText, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return jiraError
+	}
+
+	if json.Unmarshal(bodyText, jiraError) != nil {
+		if message := strings.TrimSpace(string(bodyText)); message != "" && !strings.HasPrefix(message, "<") {
+			jiraError.ErrorMessages = []string{message}
+		}
+	}
+
+	return jiraError
+}
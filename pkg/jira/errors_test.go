@@ -0,0 +1,59 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCheckResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       string
+	}{
+		{"success", 200, `{}`, ""},
+		{"no content", 204, ``, ""},
+		{
+			"field errors",
+			400,
+			`{"errorMessages":[],"errors":{"summary":"You must specify a summary","issuetype":"Specify an issue type"}}`,
+			"400 bad request: issuetype: Specify an issue type; summary: You must specify a summary",
+		},
+		{
+			"error messages",
+			404,
+			`{"errorMessages":["Issue does not exist or you do not have permission to see it."],"errors":{}}`,
+			"404 not found: Issue does not exist or you do not have permission to see it.",
+		},
+		{"bad credentials", 401, `Client must be authenticated to access this resource.`,
+			"401 bad credentials, check Jira email and API key: Client must be authenticated to access this resource."},
+		{"html body", 403, `<html><body>Forbidden</body></html>`, "403 access denied"},
+		{"empty body", 503, ``, "503 service unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.statusCode, Body: ioutil.NopCloser(strings.NewReader(tt.body))}
+			err := checkResponse(resp)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("checkResponse() = %q, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("checkResponse() = %v, want %q", err, tt.want)
+			}
+
+			var jiraError *Error
+			if !errors.As(fmt.Errorf("wrapped: %w", err), &jiraError) || jiraError.StatusCode != tt.statusCode {
+				t.Errorf("checkResponse() = %#v, want Error with status code %d", err, tt.statusCode)
+			}
+		})
+	}
+}
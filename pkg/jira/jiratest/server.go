@@ -0,0 +1,1003 @@
+// Package jiratest provides an in-process fake Jira REST API server for tests.
+//
+// The server keeps issues in memory and implements the subset of Jira Cloud REST API v3
+// used by got: getting, creating and updating issues, transitions, search by keys,
+// comments, worklogs, remote links, labels and project issue types and components. Every request is recorded
+// so tests can assert what was sent.
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIPath is a path of Jira REST API served by the fake server
+const APIPath = "/rest/api/3"
+
+// Keys of Jira status categories
+const (
+	StatusCategoryToDo       = "new"
+	StatusCategoryInProgress = "indeterminate"
+	StatusCategoryDone       = "done"
+)
+
+// Issue is an issue stored by the fake server
+type Issue struct {
+	ID             string
+	Key            string
+	Summary        string
+	Description    string
+	DescriptionADF interface{}
+	IssueType      string
+	Status         string
+	StatusCategory string
+	Labels         []string
+	Components     []string
+	Assignee       string
+	Fields         map[string]interface{}
+	Comments       []Comment
+	Worklogs       []Worklog
+	RemoteLinks    []RemoteLink
+	Updated        time.Time
+}
+
+// Comment is a comment of an issue stored by the fake server
+type Comment struct {
+	ID   string
+	Body interface{}
+}
+
+// Worklog is a worklog of an issue stored by the fake server
+type Worklog struct {
+	ID               string
+	Started          string
+	TimeSpentSeconds int
+}
+
+// RemoteLink is a remote link of an issue stored by the fake server
+type RemoteLink struct {
+	ID           int
+	GlobalID     string
+	Relationship string
+	URL          string
+	Title        string
+	Summary      string
+}
+
+// Transition is a workflow transition available for all issues
+type Transition struct {
+	ID             string
+	Name           string
+	Status         string
+	StatusCategory string
+}
+
+// Request is a request received by the fake server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   string
+}
+
+type queuedResponse struct {
+	statusCode int
+	body       string
+}
+
+// Server is a fake Jira server storing issues in memory
+type Server struct {
+	*httptest.Server
+
+	ProjectCode string
+	Transitions []Transition
+	// IssueTypes are names of issue types available in the project, other types are rejected on issue creation
+	IssueTypes []string
+	// Components are names of components of the project
+	Components []string
+
+	mutex     sync.Mutex
+	issues    map[string]*Issue
+	nextID    int
+	email     string
+	apiKey    string
+	responses []queuedResponse
+	requests  []Request
+	now       func() time.Time
+}
+
+// DefaultTransitions is a workflow of issues created by the fake server
+var DefaultTransitions = []Transition{
+	{ID: "11", Name: "To Do", Status: "To Do", StatusCategory: StatusCategoryToDo},
+	{ID: "21", Name: "In Progress", Status: "In Progress", StatusCategory: StatusCategoryInProgress},
+	{ID: "31", Name: "Done", Status: "Done", StatusCategory: StatusCategoryDone},
+}
+
+// DefaultIssueTypes are issue types available in projects of the fake server
+var DefaultIssueTypes = []string{"Story", "Task", "Bug", "Epic"}
+
+// NewServer starts fake Jira server for the project, it should be closed with Close
+func NewServer(projectCode string) *Server {
+	server := &Server{
+		ProjectCode: projectCode,
+		Transitions: DefaultTransitions,
+		IssueTypes:  DefaultIssueTypes,
+		issues:      map[string]*Issue{},
+		nextID:      10000,
+		now:         time.Now,
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	return server
+}
+
+// APIEndpoint returns Jira API endpoint of the server to be used in the client configuration
+func (server *Server) APIEndpoint() string {
+	return server.URL + APIPath
+}
+
+// RequireCredentials makes the server reject requests without the basic authentication credentials
+func (server *Server) RequireCredentials(email string, apiKey string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.email = email
+	server.apiKey = apiKey
+}
+
+// AddIssue stores issue and returns it with generated ID, key, status and type when they are empty
+func (server *Server) AddIssue(issue Issue) Issue {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return *server.addIssue(issue)
+}
+
+// Issue returns copy of the stored issue
+func (server *Server) Issue(issueKey string) (Issue, bool) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	issue, ok := server.issues[issueKey]
+	if !ok {
+		return Issue{}, false
+	}
+	return *issue, true
+}
+
+// Issues returns copies of all stored issues ordered by ID
+func (server *Server) Issues() []Issue {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	var issues []Issue
+	for _, issue := range server.sortedIssues() {
+		issues = append(issues, *issue)
+	}
+	return issues
+}
+
+// RespondWithError makes the server respond to the next request with the status code and Jira error messages.
+// Rate limit and service unavailable responses contain Retry-After header with zero delay.
+func (server *Server) RespondWithError(statusCode int, errorMessages ...string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if errorMessages == nil {
+		errorMessages = []string{}
+	}
+	body, _ := json.Marshal(map[string]interface{}{"errorMessages": errorMessages, "errors": map[string]string{}})
+	server.responses = append(server.responses, queuedResponse{statusCode: statusCode, body: string(body)})
+}
+
+// Requests returns all requests received by the server
+func (server *Server) Requests() []Request {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return append([]Request{}, server.requests...)
+}
+
+// ResetRequests forgets received requests
+func (server *Server) ResetRequests() {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.requests = nil
+}
+
+func (server *Server) addIssue(issue Issue) *Issue {
+	server.nextID++
+	if issue.ID == "" {
+		issue.ID = strconv.Itoa(server.nextID)
+	}
+	for number := len(server.issues) + 1; issue.Key == ""; number++ {
+		if _, ok := server.issues[fmt.Sprintf("%s-%d", server.ProjectCode, number)]; !ok {
+			issue.Key = fmt.Sprintf("%s-%d", server.ProjectCode, number)
+		}
+	}
+	if issue.IssueType == "" {
+		issue.IssueType = "Story"
+	}
+	if issue.Status == "" && len(server.Transitions) > 0 {
+		issue.Status = server.Transitions[0].Status
+		issue.StatusCategory = server.Transitions[0].StatusCategory
+	}
+	if issue.Updated.IsZero() {
+		issue.Updated = server.now()
+	}
+
+	server.issues[issue.Key] = &issue
+	return &issue
+}
+
+func (server *Server) sortedIssues() []*Issue {
+	var issues []*Issue
+	for _, issue := range server.issues {
+		issues = append(issues, issue)
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		left, _ := strconv.Atoi(issues[i].ID)
+		right, _ := strconv.Atoi(issues[j].ID)
+		return left < right
+	})
+	return issues
+}
+
+var (
+	issuePathPattern  = regexp.MustCompile(`^/issue/([^/]+)$`)
+	issueChildPattern = regexp.MustCompile(`^/issue/([^/]+)/(transitions|comment|worklog|remotelink)$`)
+)
+
+func (server *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.requests = append(server.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   string(body),
+	})
+
+	if len(server.responses) > 0 {
+		response := server.responses[0]
+		server.responses = server.responses[1:]
+		if response.statusCode == http.StatusTooManyRequests || response.statusCode == http.StatusServiceUnavailable {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(response.statusCode)
+		w.Write([]byte(response.body))
+		return
+	}
+
+	if server.email != "" || server.apiKey != "" {
+		email, apiKey, ok := r.BasicAuth()
+		if !ok || email != server.email || apiKey != server.apiKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("Client must be authenticated to access this resource."))
+			return
+		}
+	}
+
+	if !strings.HasPrefix(r.URL.Path, APIPath+"/") {
+		writeError(w, http.StatusNotFound, "Unknown API path")
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, APIPath)
+
+	switch {
+	case path == "/issue" || path == "/issue/":
+		server.routeMethod(w, r, body, map[string]handlerFunc{http.MethodPost: server.createIssue}, "")
+	case path == "/project/"+server.ProjectCode:
+		server.routeMethod(w, r, body, map[string]handlerFunc{http.MethodGet: server.getProject}, "")
+	case path == "/search":
+		server.routeMethod(w, r, body, map[string]handlerFunc{
+			http.MethodGet:  server.searchIssues,
+			http.MethodPost: server.searchIssues,
+		}, "")
+	case issuePathPattern.MatchString(path):
+		issueKey := issuePathPattern.FindStringSubmatch(path)[1]
+		server.routeMethod(w, r, body, map[string]handlerFunc{
+			http.MethodGet: server.getIssue,
+			http.MethodPut: server.updateIssue,
+		}, issueKey)
+	case issueChildPattern.MatchString(path):
+		matches := issueChildPattern.FindStringSubmatch(path)
+		switch matches[2] {
+		case "transitions":
+			server.routeMethod(w, r, body, map[string]handlerFunc{
+				http.MethodGet:  server.getTransitions,
+				http.MethodPost: server.transitionIssue,
+			}, matches[1])
+		case "comment":
+			server.routeMethod(w, r, body, map[string]handlerFunc{
+				http.MethodGet:  server.getComments,
+				http.MethodPost: server.addComment,
+			}, matches[1])
+		case "worklog":
+			server.routeMethod(w, r, body, map[string]handlerFunc{http.MethodPost: server.addWorklog}, matches[1])
+		case "remotelink":
+			server.routeMethod(w, r, body, map[string]handlerFunc{
+				http.MethodGet:  server.getRemoteLinks,
+				http.MethodPost: server.setRemoteLink,
+			}, matches[1])
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Unknown API path")
+	}
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, body []byte, issue *Issue)
+
+func (server *Server) routeMethod(
+	w http.ResponseWriter, r *http.Request, body []byte, handlers map[string]handlerFunc, issueKey string,
+) {
+	handler, ok := handlers[r.Method]
+	if !ok {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method %s is not allowed", r.Method))
+		return
+	}
+
+	var issue *Issue
+	if issueKey != "" {
+		issue, ok = server.issues[issueKey]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+			return
+		}
+	}
+
+	handler(w, r, body, issue)
+}
+
+func (server *Server) getIssue(w http.ResponseWriter, r *http.Request, body []byte, issue *Issue) {
+	etag := fmt.Sprintf(`"%s-%d"`, issue.ID, issue.Updated.UnixNano())
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, server.renderIssue(issue, strings.Contains(r.URL.Query().Get("expand"), "renderedFields")))
+}
+
+func (server *Server) createIssue(w http.ResponseWriter, r *http.Request, body []byte, _ *Issue) {
+	var data struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request payload. Refer to the REST API documentation and try again.")
+		return
+	}
+
+	var project struct {
+		Key string `json:"key"`
+	}
+	var issueType struct {
+		Name string `json:"name"`
+	}
+	var summary string
+	json.Unmarshal(data.Fields["project"], &project)
+	json.Unmarshal(data.Fields["issuetype"], &issueType)
+	json.Unmarshal(data.Fields["summary"], &summary)
+
+	fieldErrors := map[string]string{}
+	if project.Key != server.ProjectCode {
+		fieldErrors["project"] = "Specify a valid project ID or key"
+	}
+	if issueType.Name == "" {
+		fieldErrors["issuetype"] = "Specify an issue type"
+	} else if len(server.IssueTypes) > 0 && !stringInSlice(issueType.Name, server.IssueTypes) {
+		fieldErrors["issuetype"] = "Specify a valid issue type"
+	}
+	if strings.TrimSpace(summary) == "" {
+		fieldErrors["summary"] = "You must specify a summary of the issue."
+	}
+	if len(fieldErrors) > 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errorMessages": []string{}, "errors": fieldErrors})
+		return
+	}
+
+	issue := Issue{Summary: summary, IssueType: issueType.Name, Fields: map[string]interface{}{}}
+	for name, value := range data.Fields {
+		switch name {
+		case "project", "issuetype", "summary":
+		case "labels":
+			json.Unmarshal(value, &issue.Labels)
+		case "components":
+			setIssueField(&issue, name, value)
+		case "description":
+			issue.Description, issue.DescriptionADF = parseDescription(value)
+		default:
+			var fieldValue interface{}
+			json.Unmarshal(value, &fieldValue)
+			issue.Fields[name] = fieldValue
+		}
+	}
+
+	created := server.addIssue(issue)
+	writeJSON(w, http.StatusCreated, map[string]string{
+		"id":   created.ID,
+		"key":  created.Key,
+		"self": fmt.Sprintf("%s%s/issue/%s", server.URL, APIPath, created.ID),
+	})
+}
+
+func (server *Server) getProject(w http.ResponseWriter, r *http.Request, body []byte, _ *Issue) {
+	issueTypes := []map[string]interface{}{}
+	for _, issueType := range server.IssueTypes {
+		issueTypes = append(issueTypes, map[string]interface{}{"name": issueType, "subtask": false})
+	}
+	components := []map[string]string{}
+	for _, component := range server.Components {
+		components = append(components, map[string]string{"name": component})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":         "10000",
+		"key":        server.ProjectCode,
+		"name":       server.ProjectCode + " project",
+		"issueTypes": issueTypes,
+		"components": components,
+	})
+}
+
+func (server *Server) updateIssue(w http.ResponseWriter, r *http.Request, body []byte, issue *Issue) {
+	var data struct {
+		Fields map[string]json.RawMessage              `json:"fields"`
+		Update map[string][]map[string]json.RawMessage `json:"update"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request payload. Refer to the REST API documentation and try again.")
+		return
+	}
+
+	updated := *issue
+	updated.Labels = append([]string{}, issue.Labels...)
+	updated.Components = append([]string{}, issue.Components...)
+	updated.Fields = map[string]interface{}{}
+	for name, value := range issue.Fields {
+		updated.Fields[name] = value
+	}
+
+	fieldErrors := map[string]string{}
+	for name, value := range data.Fields {
+		if err := setIssueField(&updated, name, value); err != "" {
+			fieldErrors[name] = err
+		}
+	}
+	for name, operations := range data.Update {
+		for _, operation := range operations {
+			for operationName, value := range operation {
+				if err := updateIssueField(&updated, name, operationName, value); err != "" {
+					fieldErrors[name] = err
+				}
+			}
+		}
+	}
+	if len(fieldErrors) > 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errorMessages": []string{}, "errors": fieldErrors})
+		return
+	}
+
+	updated.Updated = server.now()
+	*issue = updated
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func setIssueField(issue *Issue, name string, value json.RawMessage) string {
+	switch name {
+	case "summary":
+		var summary string
+		if json.Unmarshal(value, &summary) != nil || strings.TrimSpace(summary) == "" {
+			return "You must specify a summary of the issue."
+		}
+		issue.Summary = summary
+	case "labels":
+		var labels []string
+		if json.Unmarshal(value, &labels) != nil {
+			return "Labels should be a list of strings"
+		}
+		for _, label := range labels {
+			if strings.Contains(label, " ") {
+				return "The label '" + label + "' contains spaces which is invalid."
+			}
+		}
+		issue.Labels = labels
+	case "components":
+		var components []struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(value, &components) != nil {
+			return "Components should be a list of objects with names"
+		}
+		issue.Components = nil
+		for _, component := range components {
+			issue.Components = append(issue.Components, component.Name)
+		}
+	case "description":
+		issue.Description, issue.DescriptionADF = parseDescription(value)
+	default:
+		var fieldValue interface{}
+		json.Unmarshal(value, &fieldValue)
+		issue.Fields[name] = fieldValue
+	}
+	return ""
+}
+
+func updateIssueField(issue *Issue, name string, operation string, value json.RawMessage) string {
+	if operation == "set" {
+		return setIssueField(issue, name, value)
+	}
+
+	var values *[]string
+	var item string
+	switch name {
+	case "labels":
+		if json.Unmarshal(value, &item) != nil {
+			return "Label should be a string"
+		}
+		if strings.Contains(item, " ") {
+			return "The label '" + item + "' contains spaces which is invalid."
+		}
+		values = &issue.Labels
+	case "components":
+		var component struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(value, &component) != nil || component.Name == "" {
+			return "Component should be an object with name"
+		}
+		item = component.Name
+		values = &issue.Components
+	default:
+		return fmt.Sprintf("Operation '%s' is not supported for field '%s'", operation, name)
+	}
+
+	switch operation {
+	case "add":
+		if !stringInSlice(item, *values) {
+			*values = append(*values, item)
+		}
+	case "remove":
+		var filtered []string
+		for _, existingItem := range *values {
+			if existingItem != item {
+				filtered = append(filtered, existingItem)
+			}
+		}
+		*values = filtered
+	default:
+		return fmt.Sprintf("Operation '%s' is not supported for field '%s'", operation, name)
+	}
+	return ""
+}
+
+func (server *Server) getTransitions(w http.ResponseWriter, r *http.Request, body []byte, issue *Issue) {
+	var transitions []map[string]interface{}
+	for _, transition := range server.Transitions {
+		if transition.Status == issue.Status {
+			continue
+		}
+		transitions = append(transitions, map[string]interface{}{
+			"id":   transition.ID,
+			"name": transition.Name,
+			"to":   renderStatus(transition.Status, transition.StatusCategory),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"transitions": transitions})
+}
+
+func (server *Server) transitionIssue(w http.ResponseWriter, r *http.Request, body []byte, issue *Issue) {
+	var data struct {
+		Transition struct {
+			ID string `json:"id"`
+		} `json:"transition"`
+	}
+	json.Unmarshal(body, &data)
+
+	for _, transition := range server.Transitions {
+		if transition.ID == data.Transition.ID && transition.Status != issue.Status {
+			issue.Status = transition.Status
+			issue.StatusCategory = transition.StatusCategory
+			issue.Updated = server.now()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"errorMessages": []string{"Transition id '" + data.Transition.ID + "' is not valid for this issue."},
+		"errors":        map[string]string{},
+	})
+}
+
+func (server *Server) getComments(w http.ResponseWriter, r *http.Request, body []byte, issue *Issue) {
+	comments := []map[string]interface{}{}
+	for _, comment := range issue.Comments {
+		comments = append(comments, map[string]interface{}{"id": comment.ID, "body": comment.Body})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    0,
+		"maxResults": len(comments),
+		"total":      len(comments),
+		"comments":   comments,
+	})
+}
+
+func (server *Server) addComment(w http.ResponseWriter, r *http.Request, body []byte, issue *Issue) {
+	var data struct {
+		Body interface{} `json:"body"`
+	}
+	if json.Unmarshal(body, &data) != nil || data.Body == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errorMessages": []string{},
+			"errors":        map[string]string{"comment": "Comment body can not be empty!"},
+		})
+		return
+	}
+
+	server.nextID++
+	comment := Comment{ID: strconv.Itoa(server.nextID), Body: data.Body}
+	issue.Comments = append(issue.Comments, comment)
+	issue.Updated = server.now()
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"id": comment.ID, "body": comment.Body})
+}
+
+func (server *Server) addWorklog(w http.ResponseWriter, r *http.Request, body []byte, issue *Issue) {
+	var data struct {
+		Started          string `json:"started"`
+		TimeSpentSeconds int    `json:"timeSpentSeconds"`
+	}
+	if json.Unmarshal(body, &data) != nil || data.TimeSpentSeconds <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errorMessages": []string{},
+			"errors":        map[string]string{"timeLogged": "You must indicate the time spent working."},
+		})
+		return
+	}
+
+	server.nextID++
+	worklog := Worklog{ID: strconv.Itoa(server.nextID), Started: data.Started, TimeSpentSeconds: data.TimeSpentSeconds}
+	issue.Worklogs = append(issue.Worklogs, worklog)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":               worklog.ID,
+		"started":          worklog.Started,
+		"timeSpentSeconds": worklog.TimeSpentSeconds,
+	})
+}
+
+// getRemoteLinks returns remote links of the issue, optionally filtered by globalId query parameter
+func (server *Server) getRemoteLinks(w http.ResponseWriter, r *http.Request, body []byte, issue *Issue) {
+	globalID := r.URL.Query().Get("globalId")
+	links := []map[string]interface{}{}
+	for _, link := range issue.RemoteLinks {
+		if globalID == "" || link.GlobalID == globalID {
+			links = append(links, renderRemoteLink(link))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, links)
+}
+
+// setRemoteLink creates remote link or updates the link with the same globalId like Jira does
+func (server *Server) setRemoteLink(w http.ResponseWriter, r *http.Request, body []byte, issue *Issue) {
+	var data struct {
+		GlobalID     string `json:"globalId"`
+		Relationship string `json:"relationship"`
+		Object       struct {
+			URL     string `json:"url"`
+			Title   string `json:"title"`
+			Summary string `json:"summary"`
+		} `json:"object"`
+	}
+	if json.Unmarshal(body, &data) != nil || data.Object.URL == "" || data.Object.Title == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"errorMessages": []string{},
+			"errors":        map[string]string{"title": "'title' is required.", "url": "'url' is required."},
+		})
+		return
+	}
+
+	link := RemoteLink{
+		GlobalID:     data.GlobalID,
+		Relationship: data.Relationship,
+		URL:          data.Object.URL,
+		Title:        data.Object.Title,
+		Summary:      data.Object.Summary,
+	}
+	issue.Updated = server.now()
+
+	for i := range issue.RemoteLinks {
+		if data.GlobalID != "" && issue.RemoteLinks[i].GlobalID == data.GlobalID {
+			link.ID = issue.RemoteLinks[i].ID
+			issue.RemoteLinks[i] = link
+			writeJSON(w, http.StatusOK, map[string]interface{}{"id": link.ID})
+			return
+		}
+	}
+
+	server.nextID++
+	link.ID = server.nextID
+	issue.RemoteLinks = append(issue.RemoteLinks, link)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"id": link.ID})
+}
+
+func renderRemoteLink(link RemoteLink) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           link.ID,
+		"globalId":     link.GlobalID,
+		"relationship": link.Relationship,
+		"object":       map[string]string{"url": link.URL, "title": link.Title, "summary": link.Summary},
+	}
+}
+
+var (
+	jqlKeyInPattern    = regexp.MustCompile(`(?i)^key\s+in\s*\((.*)\)$`)
+	jqlEqualsPattern   = regexp.MustCompile(`(?i)^(key|project|labels|status)\s*=\s*(.+)$`)
+	jqlNotEmptyPattern = regexp.MustCompile(`(?i)^labels\s+is\s+not\s+empty$`)
+	jqlAndPattern      = regexp.MustCompile(`(?i)\s+and\s+`)
+	jqlOrderByPattern  = regexp.MustCompile(`(?i)\s+order\s+by\s+.*$`)
+)
+
+// searchIssues supports JQL queries of 'key in (...)' and 'field = value' clauses
+// for key, project, labels and status fields joined with AND
+func (server *Server) searchIssues(w http.ResponseWriter, r *http.Request, body []byte, _ *Issue) {
+	data := struct {
+		JQL        string   `json:"jql"`
+		Fields     []string `json:"fields"`
+		Expand     []string `json:"expand"`
+		StartAt    int      `json:"startAt"`
+		MaxResults int      `json:"maxResults"`
+	}{MaxResults: 50}
+
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		data.JQL = query.Get("jql")
+		data.StartAt, _ = strconv.Atoi(query.Get("startAt"))
+		if maxResults, err := strconv.Atoi(query.Get("maxResults")); err == nil {
+			data.MaxResults = maxResults
+		}
+		data.Expand = strings.Split(query.Get("expand"), ",")
+	} else if err := json.Unmarshal(body, &data); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request payload. Refer to the REST API documentation and try again.")
+		return
+	}
+
+	matchers, err := parseJQL(data.JQL)
+	if err != "" {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var matchedIssues []*Issue
+	for _, issue := range server.sortedIssues() {
+		matched := true
+		for _, matcher := range matchers {
+			matched = matched && matcher(issue)
+		}
+		if matched {
+			matchedIssues = append(matchedIssues, issue)
+		}
+	}
+
+	rendered := false
+	for _, expand := range data.Expand {
+		rendered = rendered || expand == "renderedFields"
+	}
+
+	issues := []interface{}{}
+	for i := data.StartAt; i < len(matchedIssues) && len(issues) < data.MaxResults; i++ {
+		issues = append(issues, server.renderIssue(matchedIssues[i], rendered))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    data.StartAt,
+		"maxResults": data.MaxResults,
+		"total":      len(matchedIssues),
+		"issues":     issues,
+	})
+}
+
+func parseJQL(jql string) ([]func(*Issue) bool, string) {
+	jql = jqlOrderByPattern.ReplaceAllString(strings.TrimSpace(jql), "")
+	if jql == "" {
+		return nil, ""
+	}
+
+	var matchers []func(*Issue) bool
+	for _, clause := range jqlAndPattern.Split(jql, -1) {
+		clause = strings.TrimSpace(clause)
+		if matches := jqlKeyInPattern.FindStringSubmatch(clause); matches != nil {
+			var keys []string
+			for _, key := range strings.Split(matches[1], ",") {
+				keys = append(keys, unquoteJQLValue(key))
+			}
+			matchers = append(matchers, func(issue *Issue) bool {
+				return stringInSlice(issue.Key, keys)
+			})
+			continue
+		}
+
+		if jqlNotEmptyPattern.MatchString(clause) {
+			matchers = append(matchers, func(issue *Issue) bool {
+				return len(issue.Labels) > 0
+			})
+			continue
+		}
+
+		if matches := jqlEqualsPattern.FindStringSubmatch(clause); matches != nil {
+			field := strings.ToLower(matches[1])
+			value := unquoteJQLValue(matches[2])
+			matchers = append(matchers, func(issue *Issue) bool {
+				switch field {
+				case "key":
+					return issue.Key == value
+				case "project":
+					return strings.HasPrefix(issue.Key, value+"-")
+				case "labels":
+					return stringInSlice(value, issue.Labels)
+				default:
+					return strings.EqualFold(issue.Status, value)
+				}
+			})
+			continue
+		}
+
+		return nil, fmt.Sprintf("Error in the JQL Query: unsupported clause '%s'.", clause)
+	}
+
+	return matchers, ""
+}
+
+func unquoteJQLValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+		value = strings.ReplaceAll(value, `\"`, `"`)
+		value = strings.ReplaceAll(value, `\\`, `\`)
+	}
+	return value
+}
+
+func (server *Server) renderIssue(issue *Issue, rendered bool) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for name, value := range issue.Fields {
+		fields[name] = value
+	}
+
+	labels := issue.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	fields["summary"] = issue.Summary
+	fields["issuetype"] = map[string]string{"name": issue.IssueType}
+	fields["status"] = renderStatus(issue.Status, issue.StatusCategory)
+	fields["labels"] = labels
+	components := []map[string]string{}
+	for _, component := range issue.Components {
+		components = append(components, map[string]string{"name": component})
+	}
+	fields["components"] = components
+	fields["updated"] = issue.Updated.Format("2006-01-02T15:04:05.000-0700")
+	fields["description"] = issue.DescriptionADF
+	if issue.DescriptionADF == nil && issue.Description != "" {
+		fields["description"] = textToADF(issue.Description)
+	}
+	fields["assignee"] = nil
+	if issue.Assignee != "" {
+		fields["assignee"] = map[string]string{"accountId": "account-" + issue.Assignee, "displayName": issue.Assignee}
+	}
+
+	result := map[string]interface{}{
+		"id":     issue.ID,
+		"key":    issue.Key,
+		"self":   fmt.Sprintf("%s%s/issue/%s", server.URL, APIPath, issue.ID),
+		"fields": fields,
+	}
+	if rendered {
+		description := ""
+		if issue.Description != "" {
+			description = "<p>" + strings.ReplaceAll(html.EscapeString(issue.Description), "\n", "<br/>") + "</p>"
+		}
+		result["renderedFields"] = map[string]string{"description": description}
+	}
+
+	return result
+}
+
+func renderStatus(name string, category string) map[string]interface{} {
+	categoryNames := map[string]string{
+		StatusCategoryToDo:       "To Do",
+		StatusCategoryInProgress: "In Progress",
+		StatusCategoryDone:       "Done",
+	}
+	return map[string]interface{}{
+		"name":           name,
+		"statusCategory": map[string]string{"key": category, "name": categoryNames[category]},
+	}
+}
+
+// parseDescription returns plain text of description passed as string or Atlassian Document Format document
+func parseDescription(value json.RawMessage) (string, interface{}) {
+	var text string
+	if json.Unmarshal(value, &text) == nil {
+		return text, nil
+	}
+
+	var document interface{}
+	json.Unmarshal(value, &document)
+	return strings.TrimSpace(extractADFText(document)), document
+}
+
+func extractADFText(node interface{}) string {
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if text, ok := object["text"].(string); ok {
+		return text
+	}
+
+	var text strings.Builder
+	children, _ := object["content"].([]interface{})
+	for _, child := range children {
+		text.WriteString(extractADFText(child))
+	}
+	switch object["type"] {
+	case "paragraph", "heading", "codeBlock", "listItem", "hardBreak":
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+func textToADF(text string) map[string]interface{} {
+	var paragraphs []interface{}
+	for _, paragraph := range strings.Split(text, "\n") {
+		content := []interface{}{}
+		if paragraph != "" {
+			content = append(content, map[string]string{"type": "text", "text": paragraph})
+		}
+		paragraphs = append(paragraphs, map[string]interface{}{"type": "paragraph", "content": content})
+	}
+	return map[string]interface{}{"type": "doc", "version": 1, "content": paragraphs}
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{"errorMessages": []string{message}, "errors": map[string]string{}})
+}
+
+func stringInSlice(a string, list []string) bool {
+	for _, b := range list {
+		if b == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func sendRequest(t *testing.T, server *Server, method string, path string, body string) (int, map[string]interface{}) {
+	req, err := http.NewRequest(method, server.APIEndpoint()+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var response map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&response)
+	return resp.StatusCode, response
+}
+
+func TestTransitions(t *testing.T) {
+	server := NewServer("PC")
+	defer server.Close()
+	server.AddIssue(Issue{Summary: "Workflow"})
+
+	statusCode, response := sendRequest(t, server, http.MethodGet, "/issue/PC-1/transitions", "")
+	if statusCode != http.StatusOK || len(response["transitions"].([]interface{})) != 2 {
+		t.Fatalf("GET transitions = %d %v, want 2 transitions", statusCode, response)
+	}
+
+	statusCode, _ = sendRequest(t, server, http.MethodPost, "/issue/PC-1/transitions", `{"transition":{"id":"31"}}`)
+	if statusCode != http.StatusNoContent {
+		t.Fatalf("POST transition = %d, want 204", statusCode)
+	}
+	if issue, _ := server.Issue("PC-1"); issue.Status != "Done" || issue.StatusCategory != StatusCategoryDone {
+		t.Errorf("transitioned issue status = %s (%s), want Done", issue.Status, issue.StatusCategory)
+	}
+
+	statusCode, _ = sendRequest(t, server, http.MethodPost, "/issue/PC-1/transitions", `{"transition":{"id":"31"}}`)
+	if statusCode != http.StatusBadRequest {
+		t.Errorf("POST transition to the current status = %d, want 400", statusCode)
+	}
+}
+
+func TestComments(t *testing.T) {
+	server := NewServer("PC")
+	defer server.Close()
+	server.AddIssue(Issue{Summary: "Comments"})
+
+	statusCode, _ := sendRequest(t, server, http.MethodPost, "/issue/PC-1/comment", `{"body":"Looks good"}`)
+	if statusCode != http.StatusCreated {
+		t.Fatalf("POST comment = %d, want 201", statusCode)
+	}
+
+	statusCode, response := sendRequest(t, server, http.MethodGet, "/issue/PC-1/comment", "")
+	if statusCode != http.StatusOK || response["total"] != float64(1) {
+		t.Errorf("GET comments = %d %v, want 1 comment", statusCode, response)
+	}
+
+	statusCode, _ = sendRequest(t, server, http.MethodPost, "/issue/PC-2/comment", `{"body":"Missing"}`)
+	if statusCode != http.StatusNotFound {
+		t.Errorf("POST comment of missing issue = %d, want 404", statusCode)
+	}
+}
+
+func TestRemoteLinks(t *testing.T) {
+	server := NewServer("PC")
+	defer server.Close()
+	server.AddIssue(Issue{Summary: "Remote links"})
+
+	link := `{"globalId":"branch-1","object":{"url":"https://example.com/tree/PC-1","title":"%s"}}`
+	statusCode, created := sendRequest(t, server, http.MethodPost, "/issue/PC-1/remotelink", fmt.Sprintf(link, "PC-1"))
+	if statusCode != http.StatusCreated {
+		t.Fatalf("POST remote link = %d, want 201", statusCode)
+	}
+
+	statusCode, updated := sendRequest(t, server, http.MethodPost, "/issue/PC-1/remotelink", fmt.Sprintf(link, "PC-1 renamed"))
+	if statusCode != http.StatusOK || updated["id"] != created["id"] {
+		t.Errorf("POST remote link with the same globalId = %d %v, want 200 and id %v", statusCode, updated, created["id"])
+	}
+	if issue, _ := server.Issue("PC-1"); len(issue.RemoteLinks) != 1 || issue.RemoteLinks[0].Title != "PC-1 renamed" {
+		t.Errorf("remote links = %+v, want one updated link", issue.RemoteLinks)
+	}
+
+	statusCode, _ = sendRequest(t, server, http.MethodPost, "/issue/PC-1/remotelink", `{"globalId":"branch-2","object":{}}`)
+	if statusCode != http.StatusBadRequest {
+		t.Errorf("POST remote link without url = %d, want 400", statusCode)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	server := NewServer("PC")
+	defer server.Close()
+	server.AddIssue(Issue{Summary: "First", Labels: []string{"backend"}})
+	server.AddIssue(Issue{Summary: "Second"})
+	server.AddIssue(Issue{Summary: "Third", Labels: []string{"backend"}})
+
+	tests := []struct {
+		jql       string
+		wantTotal float64
+	}{
+		{`key in ("PC-1", "PC-2", "PC-9")`, 2},
+		{`project = PC AND labels = backend ORDER BY key`, 2},
+		{`key = "PC-3"`, 1},
+		{``, 3},
+	}
+
+	for _, tt := range tests {
+		body, _ := json.Marshal(map[string]interface{}{"jql": tt.jql, "fields": []string{"summary"}})
+		statusCode, response := sendRequest(t, server, http.MethodPost, "/search", string(body))
+		if statusCode != http.StatusOK || response["total"] != tt.wantTotal {
+			t.Errorf("search %q = %d %v, want %v issues", tt.jql, statusCode, response["total"], tt.wantTotal)
+		}
+	}
+
+	statusCode, _ := sendRequest(t, server, http.MethodPost, "/search", `{"jql":"summary ~ first"}`)
+	if statusCode != http.StatusBadRequest {
+		t.Errorf("search with unsupported JQL = %d, want 400", statusCode)
+	}
+}
@@ -0,0 +1,199 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+type idempotentRequestKey struct{}
+
+// RateLimitError is returned when Jira keeps rejecting requests because of rate limits
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (err RateLimitError) Error() string {
+	if err.RetryAfter > 0 {
+		return fmt.Sprintf("Jira rate limit exceeded, try again in %s", err.RetryAfter.Round(time.Second))
+	}
+	return "Jira rate limit exceeded, try again later"
+}
+
+// retryNotifier prints messages about retried requests
+var retryNotifier = func(message string) {
+	fmt.Fprintln(os.Stderr, message)
+}
+
+// retryTransport retries Jira requests rejected because of rate limits and
+// idempotent requests failed with temporary server or network errors
+type retryTransport struct {
+	transport  http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+func newRetryTransport(transport http.RoundTripper, maxRetries int) *retryTransport {
+	return &retryTransport{
+		transport:  transport,
+		maxRetries: maxRetries,
+		baseDelay:  retryBaseDelay,
+		maxDelay:   retryMaxDelay,
+	}
+}
+
+// withIdempotentRequest marks requests with the context as safe to retry
+// even if HTTP method is not idempotent, e.g. POST request of issues search
+func withIdempotentRequest(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentRequestKey{}, true)
+}
+
+func (transport *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := transport.transport.RoundTrip(req)
+		if attempt > transport.maxRetries || !isRetryableResponse(req, resp, err) {
+			return resp, err
+		}
+
+		delay := transport.getBackoffDelay(attempt)
+		if resp != nil {
+			if retryDelay, ok := getRetryDelay(resp, time.Now()); ok {
+				delay = retryDelay
+			}
+		}
+
+		deadline, hasDeadline := req.Context().Deadline()
+		if hasDeadline && time.Now().Add(delay).After(deadline) {
+			return resp, err
+		}
+
+		nextReq, bodyErr := rewindRequest(req)
+		if bodyErr != nil {
+			return resp, err
+		}
+
+		retryNotifier(fmt.Sprintf(
+			"%s, retrying in %s (%d of %d)",
+			describeRetryReason(resp, err), delay.Round(100*time.Millisecond), attempt, transport.maxRetries,
+		))
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		req = nextReq
+	}
+}
+
+// getBackoffDelay returns exponential delay with full jitter for the retry attempt
+func (transport *retryTransport) getBackoffDelay(attempt int) time.Duration {
+	delay := transport.maxDelay
+	if attempt < 32 {
+		if exponentialDelay := transport.baseDelay << uint(attempt-1); exponentialDelay > 0 && exponentialDelay < delay {
+			delay = exponentialDelay
+		}
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// isRetryableResponse reports whether request may be sent again. Rate limited requests are
+// not processed by Jira so they are always retried, other failures only for idempotent requests.
+func isRetryableResponse(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return isIdempotentRequest(req) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotentRequest(req)
+	}
+	return false
+}
+
+func isIdempotentRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	idempotent, _ := req.Context().Value(idempotentRequestKey{}).(bool)
+	return idempotent
+}
+
+// getRetryDelay returns delay requested by Jira with Retry-After header
+// or X-RateLimit-Reset header when rate limit is exhausted
+func getRetryDelay(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return maxDuration(date.Sub(now), 0), true
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := time.Parse(time.RFC3339, resp.Header.Get("X-RateLimit-Reset")); err == nil {
+			return maxDuration(reset.Sub(now), 0), true
+		}
+	}
+
+	return 0, false
+}
+
+func maxDuration(a time.Duration, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func describeRetryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return fmt.Sprintf("Jira request failed: %s", err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return "Jira rate limit hit"
+	}
+	return fmt.Sprintf("Jira is temporarily unavailable (status code %d)", resp.StatusCode)
+}
+
+// rewindRequest returns copy of the request with fresh body to send it again
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	nextReq := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return nextReq, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("Request body can not be sent again")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	nextReq.Body = body
+	return nextReq, nil
+}
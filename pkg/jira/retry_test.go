@@ -0,0 +1,162 @@
+package jira
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRetryClient(maxRetries int) *http.Client {
+	transport := newRetryTransport(http.DefaultTransport, maxRetries)
+	transport.baseDelay = time.Millisecond
+	transport.maxDelay = 10 * time.Millisecond
+	return &http.Client{Transport: transport}
+}
+
+// silenceRetryNotifier discards retry messages during the test
+func silenceRetryNotifier(t *testing.T) {
+	savedNotifier := retryNotifier
+	retryNotifier = func(string) {}
+	t.Cleanup(func() { retryNotifier = savedNotifier })
+}
+
+func TestRetryTransport(t *testing.T) {
+	silenceRetryNotifier(t)
+
+	tests := []struct {
+		name         string
+		method       string
+		idempotent   bool
+		statusCodes  []int
+		maxRetries   int
+		wantStatus   int
+		wantRequests int
+	}{
+		{"success", http.MethodGet, false, []int{200}, 3, 200, 1},
+		{"rate limited GET", http.MethodGet, false, []int{429, 429, 200}, 3, 200, 3},
+		{"rate limited POST", http.MethodPost, false, []int{429, 201}, 3, 201, 2},
+		{"unavailable PUT", http.MethodPut, false, []int{503, 204}, 3, 204, 2},
+		{"unavailable POST", http.MethodPost, false, []int{503, 201}, 3, 503, 1},
+		{"unavailable idempotent POST", http.MethodPost, true, []int{503, 200}, 3, 200, 2},
+		{"retries exhausted", http.MethodGet, false, []int{429, 429, 429}, 2, 429, 3},
+		{"retries disabled", http.MethodGet, false, []int{429, 200}, 0, 429, 1},
+		{"client error", http.MethodGet, false, []int{404, 200}, 3, 404, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(tt.statusCodes[requests])
+				requests++
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			if tt.idempotent {
+				ctx = withIdempotentRequest(ctx)
+			}
+			req, err := http.NewRequestWithContext(ctx, tt.method, server.URL, strings.NewReader("payload"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := newTestRetryClient(tt.maxRetries).Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			for _, body := range bodies {
+				if body != "payload" {
+					t.Errorf("request body = %q, want %q", body, "payload")
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportDeadline(t *testing.T) {
+	silenceRetryNotifier(t)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := newTestRetryClient(3).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1 when Retry-After exceeds deadline", requests)
+	}
+}
+
+func TestGetRetryDelay(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+		wantOK  bool
+	}{
+		{"no headers", nil, 0, false},
+		{"seconds", map[string]string{"Retry-After": "5"}, 5 * time.Second, true},
+		{"zero seconds", map[string]string{"Retry-After": "0"}, 0, true},
+		{"http date", map[string]string{"Retry-After": "Mon, 01 Mar 2021 12:00:10 GMT"}, 10 * time.Second, true},
+		{"past http date", map[string]string{"Retry-After": "Mon, 01 Mar 2021 11:00:00 GMT"}, 0, true},
+		{"invalid", map[string]string{"Retry-After": "soon"}, 0, false},
+		{
+			"rate limit reset",
+			map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "2021-03-01T12:00:30Z"},
+			30 * time.Second,
+			true,
+		},
+		{
+			"rate limit not exhausted",
+			map[string]string{"X-RateLimit-Remaining": "10", "X-RateLimit-Reset": "2021-03-01T12:00:30Z"},
+			0,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			for key, value := range tt.headers {
+				resp.Header.Set(key, value)
+			}
+			if got, ok := getRetryDelay(resp, now); got != tt.want || ok != tt.wantOK {
+				t.Errorf("getRetryDelay() = %s, %t, want %s, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
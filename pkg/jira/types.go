@@ -2,6 +2,7 @@ package jira
 
 import (
 	"regexp"
+	"strings"
 )
 
 // Issue is a struct for Jira issue
@@ -9,13 +10,49 @@ type Issue struct {
 	ID     string `json:"id"`
 	Key    string `json:"key"`
 	Fields struct {
-		Summary string `json:"summary"`
+		Summary   string          `json:"summary"`
+		Status    IssueStatus     `json:"status"`
+		IssueType IssueTypeFields `json:"issuetype"`
+		Assignee  *IssueUser      `json:"assignee"`
+		Labels    []string        `json:"labels"`
+		Updated   string          `json:"updated"`
+		// Description is nil for issues without description and for issues cached before it was stored
+		Description *ADFNode `json:"description"`
 	} `json:"fields"`
 	RenderedFields struct {
 		Description string `json:"description"`
 	} `json:"renderedFields"`
 }
 
+// IssueStatus is a type for Jira issue workflow status
+type IssueStatus struct {
+	Name           string              `json:"name"`
+	StatusCategory IssueStatusCategory `json:"statusCategory"`
+}
+
+// IssueUser is a type for Jira user assigned to issue
+type IssueUser struct {
+	AccountID   string `json:"accountId"`
+	DisplayName string `json:"displayName"`
+}
+
+// IssueTypeFields is a type for Jira issue type
+type IssueTypeFields struct {
+	Name string `json:"name"`
+}
+
+// IssueStatusCategory is a type for category of Jira issue workflow status
+type IssueStatusCategory struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// Keys of Jira status categories
+const (
+	statusCategoryInProgress = "indeterminate"
+	statusCategoryDone       = "done"
+)
+
 // CreateIssueData is a struct for Jira Issue form values
 type CreateIssueData struct {
 	Fields CreateIssueDataFields `json:"fields"`
@@ -23,9 +60,13 @@ type CreateIssueData struct {
 
 // CreateIssueDataFields is a type for IssueForm nested structure
 type CreateIssueDataFields struct {
-	Project   CreateIssueDataProject   `json:"project"`
-	Summary   string                   `json:"summary"`
-	IssueType CreateIssueDataIssueType `json:"issuetype"`
+	Project     CreateIssueDataProject   `json:"project"`
+	Summary     string                   `json:"summary"`
+	IssueType   CreateIssueDataIssueType `json:"issuetype"`
+	Description *ADFNode                 `json:"description,omitempty"`
+	Components  []IssueComponent         `json:"components,omitempty"`
+	Labels      []string                 `json:"labels,omitempty"`
+	Priority    *CreateIssueDataPriority `json:"priority,omitempty"`
 }
 
 // CreateIssueDataProject is a type for IssueForm nested structure
@@ -38,6 +79,37 @@ type CreateIssueDataIssueType struct {
 	Name string `json:"name"`
 }
 
+// CreateIssueDataPriority is a type for IssueForm nested structure
+type CreateIssueDataPriority struct {
+	Name string `json:"name"`
+}
+
+// NewIssue is a type for fields of Jira issue to be created
+type NewIssue struct {
+	Summary string
+	// IssueType is a name of the issue type, DefaultIssueType is used when it is empty
+	IssueType   string
+	Description *ADFNode
+	Components  []string
+	Labels      []string
+	// Priority is a name of the issue priority, project default priority is used when it is empty
+	Priority string
+}
+
+// Project is a type for Jira project with issue types and components available in it
+type Project struct {
+	Key        string             `json:"key"`
+	Name       string             `json:"name"`
+	IssueTypes []ProjectIssueType `json:"issueTypes"`
+	Components []IssueComponent   `json:"components"`
+}
+
+// ProjectIssueType is a type for issue type available in Jira project
+type ProjectIssueType struct {
+	Name    string `json:"name"`
+	Subtask bool   `json:"subtask"`
+}
+
 // CreateIssueResponse type for response on Jira issue creation request
 type CreateIssueResponse struct {
 	ID   string `json:"id"`
@@ -47,17 +119,42 @@ type CreateIssueResponse struct {
 
 // UpdateIssueData is a type for issue update request data
 type UpdateIssueData struct {
-	Update UpdateIssueDataFields `json:"update"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	Update UpdateIssueDataFields  `json:"update"`
 }
 
 // UpdateIssueDataFields is a type for issue update data fields
 type UpdateIssueDataFields struct {
 	Summary []UpdateIssueSummaryFieldOperationData `json:"summary,omitempty"`
 	Labels []UpdateIssueLabels `json:"labels,omitempty"`
+	Components []UpdateIssueComponents `json:"components,omitempty"`
 }
 
 type UpdateIssueLabels struct {
-	Add string `json:"add"`
+	Add    string   `json:"add,omitempty"`
+	Remove string   `json:"remove,omitempty"`
+	Set    []string `json:"set,omitempty"`
+}
+
+// UpdateIssueComponents is a type for issue component add operation
+type UpdateIssueComponents struct {
+	Add IssueComponent `json:"add"`
+}
+
+// IssueComponent is a type for Jira project component referenced by name
+type IssueComponent struct {
+	Name string `json:"name"`
+}
+
+// IssueUpdate is a type for labels and components added to issue and fields set for it
+type IssueUpdate struct {
+	Labels       []string
+	RemoveLabels []string
+	// SetLabels replaces labels of the issue when it is not empty
+	SetLabels  []string
+	Components []string
+	// Fields are set as is, e.g. {"customfield_10001": {"value": "Payments"}}
+	Fields map[string]interface{}
 }
 
 // UpdateIssueSummaryFieldOperationData is a type for issue summary set operation
@@ -65,8 +162,77 @@ type UpdateIssueSummaryFieldOperationData struct {
 	Set string `json:"set"`
 }
 
+// SearchIssuesData is a type for issues search request data
+type SearchIssuesData struct {
+	JQL           string   `json:"jql"`
+	Fields        []string `json:"fields"`
+	Expand        []string `json:"expand,omitempty"`
+	StartAt       int      `json:"startAt"`
+	MaxResults    int      `json:"maxResults"`
+	ValidateQuery string   `json:"validateQuery,omitempty"`
+}
+
+// SearchIssuesResponse is a type for response on issues search request
+type SearchIssuesResponse struct {
+	StartAt    int     `json:"startAt"`
+	MaxResults int     `json:"maxResults"`
+	Total      int     `json:"total"`
+	Issues     []Issue `json:"issues"`
+}
+
+// worklogTimeLayout is a format of time values accepted by Jira worklog api
+const worklogTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// AddWorklogData is a type for issue worklog creation request data
+type AddWorklogData struct {
+	Started          string `json:"started"`
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+}
+
+// RemoteLink is a type for link of Jira issue to an object outside of Jira, like a branch or a pull request.
+// Links with the same GlobalID are updated instead of being added again.
+type RemoteLink struct {
+	GlobalID     string           `json:"globalId"`
+	Relationship string           `json:"relationship,omitempty"`
+	Object       RemoteLinkObject `json:"object"`
+}
+
+// RemoteLinkObject is a type for object of Jira remote link
+type RemoteLinkObject struct {
+	URL     string `json:"url"`
+	Title   string `json:"title"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// IsDone checks if issue status belongs to Done status category
+func (issue Issue) IsDone() bool {
+	return issue.Fields.Status.StatusCategory.Key == statusCategoryDone
+}
+
+// IsInProgress checks if issue status belongs to In Progress status category
+func (issue Issue) IsInProgress() bool {
+	return issue.Fields.Status.StatusCategory.Key == statusCategoryInProgress
+}
+
+// GetAssigneeName returns display name of the assigned user or empty string for unassigned issues
+func (issue Issue) GetAssigneeName() string {
+	if issue.Fields.Assignee == nil {
+		return ""
+	}
+	return issue.Fields.Assignee.DisplayName
+}
+
 // GetStrippedDescription returns issues descriptions without html tags
 func (issue Issue) GetStrippedDescription() string {
 	reg := regexp.MustCompile("<.*?>")
 	return reg.ReplaceAllString(issue.RenderedFields.Description, "")
 }
+
+// GetMarkdownDescription returns issue description converted to Markdown, rendered description without html tags is
+// used when the description document is not available
+func (issue Issue) GetMarkdownDescription() string {
+	if issue.Fields.Description == nil {
+		return strings.TrimSpace(issue.GetStrippedDescription())
+	}
+	return ADFToMarkdown(*issue.Fields.Description)
+}
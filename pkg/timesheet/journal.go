@@ -0,0 +1,143 @@
+package timesheet
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	journalFileName  = "checkouts.jsonl"
+	worklogsFileName = "worklogs.jsonl"
+	dayLayout        = "2006-01-02"
+)
+
+// Entry is a record of switching to a branch
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Repository string    `json:"repository"`
+	Branch     string    `json:"branch"`
+}
+
+// SubmittedWorklog is a record of time already submitted to Jira
+type SubmittedWorklog struct {
+	Day      string `json:"day"`
+	IssueKey string `json:"issueKey"`
+	Seconds  int    `json:"seconds"`
+}
+
+// Journal stores branch switches and submitted worklogs in a local directory
+type Journal struct {
+	Directory string
+}
+
+// DefaultJournal returns journal stored in the user data directory
+func DefaultJournal() (Journal, error) {
+	dataDirectory := os.Getenv("XDG_DATA_HOME")
+	if dataDirectory == "" {
+		homeDirectory, err := os.UserHomeDir()
+		if err != nil {
+			return Journal{}, fmt.Errorf("Failed to find user home directory: %s", err.Error())
+		}
+		dataDirectory = filepath.Join(homeDirectory, ".local", "share")
+	}
+
+	return Journal{Directory: filepath.Join(dataDirectory, "got")}, nil
+}
+
+// AddEntry appends branch switch record to the journal
+func (journal Journal) AddEntry(entry Entry) error {
+	return journal.appendLine(journalFileName, entry)
+}
+
+// ReadEntries returns all branch switch records ordered by time
+func (journal Journal) ReadEntries() ([]Entry, error) {
+	var entries []Entry
+	err := journal.readLines(journalFileName, func(line []byte) error {
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+
+	sortEntries(entries)
+	return entries, err
+}
+
+// AddSubmittedWorklog records that time was submitted to Jira
+func (journal Journal) AddSubmittedWorklog(worklog SubmittedWorklog) error {
+	return journal.appendLine(worklogsFileName, worklog)
+}
+
+// ReadSubmittedWorklogs returns all records of time submitted to Jira
+func (journal Journal) ReadSubmittedWorklogs() ([]SubmittedWorklog, error) {
+	var worklogs []SubmittedWorklog
+	err := journal.readLines(worklogsFileName, func(line []byte) error {
+		var worklog SubmittedWorklog
+		if err := json.Unmarshal(line, &worklog); err != nil {
+			return err
+		}
+		worklogs = append(worklogs, worklog)
+		return nil
+	})
+
+	return worklogs, err
+}
+
+func (journal Journal) appendLine(fileName string, value interface{}) error {
+	line, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(journal.Directory, 0755)
+	if err != nil {
+		return fmt.Errorf("Failed to create journal directory '%s': %s", journal.Directory, err.Error())
+	}
+
+	filePath := filepath.Join(journal.Directory, fileName)
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to open journal file '%s': %s", filePath, err.Error())
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("Failed to write to journal file '%s': %s", filePath, err.Error())
+	}
+
+	return nil
+}
+
+func (journal Journal) readLines(fileName string, parseLine func([]byte) error) error {
+	filePath := filepath.Join(journal.Directory, fileName)
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to open journal file '%s': %s", filePath, err.Error())
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if err := parseLine(scanner.Bytes()); err != nil {
+			return fmt.Errorf("Failed to parse line %d of journal file '%s': %s", lineNumber, filePath, err.Error())
+		}
+	}
+
+	return scanner.Err()
+}
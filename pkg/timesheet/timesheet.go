@@ -0,0 +1,166 @@
+package timesheet
+
+import (
+	"sort"
+	"time"
+)
+
+// MaxSessionDuration limits time counted for a single branch checkout so
+// that a branch left checked out overnight does not count as a work day
+const MaxSessionDuration = 8 * time.Hour
+
+// Record is time spent on a Jira issue during a day
+type Record struct {
+	Day       time.Time
+	IssueKey  string
+	Started   time.Time
+	Duration  time.Duration
+	Submitted time.Duration
+}
+
+// Unsubmitted returns time of the record not yet submitted to Jira
+func (record Record) Unsubmitted() time.Duration {
+	if record.Submitted >= record.Duration {
+		return 0
+	}
+	return record.Duration - record.Submitted
+}
+
+// DayString returns day of the record in the journal format
+func (record Record) DayString() string {
+	return record.Day.Format(dayLayout)
+}
+
+// Summarize converts branch switch entries into time spent per issue per day.
+// Every entry starts a session that lasts until the next entry of the same repository, but not longer than maxSession.
+// Sessions of branches that are not linked to issues and of detached HEAD entries with empty branch are skipped.
+// Overlapping sessions of an issue in several repositories are counted once.
+func Summarize(
+	entries []Entry, from time.Time, to time.Time, maxSession time.Duration, getIssueKey func(branch string) string,
+) []Record {
+	sortEntries(entries)
+
+	entriesByRepository := map[string][]Entry{}
+	for _, entry := range entries {
+		entriesByRepository[entry.Repository] = append(entriesByRepository[entry.Repository], entry)
+	}
+
+	sessionsByIssueKey := map[string][]session{}
+	for _, repositoryEntries := range entriesByRepository {
+		for i, entry := range repositoryEntries {
+			issueKey := getIssueKey(entry.Branch)
+			if issueKey == "" {
+				continue
+			}
+
+			start := entry.Time.In(to.Location())
+			end := to
+			if i+1 < len(repositoryEntries) {
+				end = repositoryEntries[i+1].Time.In(to.Location())
+			}
+			if maxSession > 0 && end.Sub(start) > maxSession {
+				end = start.Add(maxSession)
+			}
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if start.Before(end) {
+				sessionsByIssueKey[issueKey] = append(sessionsByIssueKey[issueKey], session{start: start, end: end})
+			}
+		}
+	}
+
+	type recordKey struct {
+		day      string
+		issueKey string
+	}
+	recordsByKey := map[recordKey]*Record{}
+
+	for issueKey, sessions := range sessionsByIssueKey {
+		for _, session := range mergeSessions(sessions) {
+			start := session.start
+			for start.Before(session.end) {
+				day := StartOfDay(start)
+				sessionEnd := day.AddDate(0, 0, 1)
+				if sessionEnd.After(session.end) {
+					sessionEnd = session.end
+				}
+
+				key := recordKey{day: day.Format(dayLayout), issueKey: issueKey}
+				record, ok := recordsByKey[key]
+				if !ok {
+					record = &Record{Day: day, IssueKey: issueKey, Started: start}
+					recordsByKey[key] = record
+				}
+				record.Duration += sessionEnd.Sub(start)
+
+				start = sessionEnd
+			}
+		}
+	}
+
+	records := make([]Record, 0, len(recordsByKey))
+	for _, record := range recordsByKey {
+		records = append(records, *record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].Day.Equal(records[j].Day) {
+			return records[i].Day.Before(records[j].Day)
+		}
+		return records[i].IssueKey < records[j].IssueKey
+	})
+
+	return records
+}
+
+// ApplySubmittedWorklogs fills submitted time of records from worklogs submitted earlier
+func ApplySubmittedWorklogs(records []Record, worklogs []SubmittedWorklog) {
+	for i := range records {
+		for _, worklog := range worklogs {
+			if worklog.Day == records[i].DayString() && worklog.IssueKey == records[i].IssueKey {
+				records[i].Submitted += time.Duration(worklog.Seconds) * time.Second
+			}
+		}
+	}
+}
+
+// StartOfDay returns midnight of the day of specified time
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// session is a time interval of work on an issue
+type session struct {
+	start time.Time
+	end   time.Time
+}
+
+// mergeSessions returns sessions ordered by start with overlapping sessions joined
+func mergeSessions(sessions []session) []session {
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].start.Before(sessions[j].start)
+	})
+
+	var merged []session
+	for _, current := range sessions {
+		last := len(merged) - 1
+		if last >= 0 && !current.start.After(merged[last].end) {
+			if current.end.After(merged[last].end) {
+				merged[last].end = current.end
+			}
+			continue
+		}
+		merged = append(merged, current)
+	}
+	return merged
+}
+
+func sortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+}
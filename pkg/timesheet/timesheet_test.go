@@ -0,0 +1,132 @@
+package timesheet
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func getTestIssueKey(branch string) string {
+	if strings.HasPrefix(branch, "PC-") {
+		return strings.Split(branch, "/")[0]
+	}
+	return ""
+}
+
+func TestSummarize_SplitsTimeBetweenIssues(t *testing.T) {
+	day := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: day.Add(10 * time.Hour), Branch: "PC-1/first"},
+		{Time: day.Add(11 * time.Hour), Branch: "PC-2/second"},
+		{Time: day.Add(11*time.Hour + 30*time.Minute), Branch: "master"},
+		{Time: day.Add(12 * time.Hour), Branch: "PC-1/first"},
+	}
+
+	records := Summarize(entries, day, day.Add(13*time.Hour), MaxSessionDuration, getTestIssueKey)
+
+	if len(records) != 2 {
+		t.Fatalf("Summarize returned %d records, want 2", len(records))
+	}
+	if records[0].IssueKey != "PC-1" || records[0].Duration != 2*time.Hour {
+		t.Errorf("Summarize returned %+v for PC-1, want 2h", records[0])
+	}
+	if !records[0].Started.Equal(day.Add(10 * time.Hour)) {
+		t.Errorf("Summarize returned start %s for PC-1, want %s", records[0].Started, day.Add(10*time.Hour))
+	}
+	if records[1].IssueKey != "PC-2" || records[1].Duration != 30*time.Minute {
+		t.Errorf("Summarize returned %+v for PC-2, want 30m", records[1])
+	}
+}
+
+func TestSummarize_LimitsSessionDuration(t *testing.T) {
+	day := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: day.Add(18 * time.Hour), Branch: "PC-1/first"},
+		{Time: day.Add(33 * time.Hour), Branch: "PC-2/second"},
+	}
+
+	records := Summarize(entries, day, day.Add(34*time.Hour), 4*time.Hour, getTestIssueKey)
+
+	if len(records) != 2 {
+		t.Fatalf("Summarize returned %d records, want 2", len(records))
+	}
+	if records[0].Duration != 4*time.Hour {
+		t.Errorf("Summarize returned %s for PC-1, want 4h", records[0].Duration)
+	}
+	if records[1].Duration != time.Hour {
+		t.Errorf("Summarize returned %s for PC-2, want 1h", records[1].Duration)
+	}
+}
+
+func TestSummarize_SplitsSessionsAtMidnight(t *testing.T) {
+	day := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: day.Add(22 * time.Hour), Branch: "PC-1/first"},
+	}
+
+	records := Summarize(entries, day, day.Add(25*time.Hour), MaxSessionDuration, getTestIssueKey)
+
+	if len(records) != 2 {
+		t.Fatalf("Summarize returned %d records, want 2", len(records))
+	}
+	if records[0].DayString() != "2021-03-01" || records[0].Duration != 2*time.Hour {
+		t.Errorf("Summarize returned %+v for the first day, want 2h on 2021-03-01", records[0])
+	}
+	if records[1].DayString() != "2021-03-02" || records[1].Duration != time.Hour {
+		t.Errorf("Summarize returned %+v for the second day, want 1h on 2021-03-02", records[1])
+	}
+}
+
+func TestSummarize_SeparatesRepositories(t *testing.T) {
+	day := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: day.Add(10 * time.Hour), Repository: "/src/api", Branch: "PC-1/api"},
+		{Time: day.Add(10*time.Hour + 30*time.Minute), Repository: "/src/web", Branch: "master"},
+		{Time: day.Add(11 * time.Hour), Repository: "/src/web", Branch: "PC-1/web"},
+		{Time: day.Add(12 * time.Hour), Repository: "/src/api", Branch: "PC-2/api"},
+		{Time: day.Add(13 * time.Hour), Repository: "/src/web", Branch: "master"},
+		{Time: day.Add(13 * time.Hour), Repository: "/src/api", Branch: "master"},
+	}
+
+	records := Summarize(entries, day, day.Add(14*time.Hour), MaxSessionDuration, getTestIssueKey)
+
+	if len(records) != 2 {
+		t.Fatalf("Summarize returned %d records, want 2", len(records))
+	}
+	if records[0].IssueKey != "PC-1" || records[0].Duration != 3*time.Hour {
+		t.Errorf("Summarize returned %+v for PC-1, want 3h counting overlapping sessions once", records[0])
+	}
+	if records[1].IssueKey != "PC-2" || records[1].Duration != time.Hour {
+		t.Errorf("Summarize returned %+v for PC-2, want 1h", records[1])
+	}
+}
+
+func TestSummarize_EndsSessionOnDetachedHead(t *testing.T) {
+	day := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: day.Add(10 * time.Hour), Branch: "PC-1/first"},
+		{Time: day.Add(11 * time.Hour), Branch: ""},
+	}
+
+	records := Summarize(entries, day, day.Add(14*time.Hour), MaxSessionDuration, getTestIssueKey)
+
+	if len(records) != 1 || records[0].Duration != time.Hour {
+		t.Errorf("Summarize returned %+v, want 1h for PC-1", records)
+	}
+}
+
+func TestApplySubmittedWorklogs(t *testing.T) {
+	records := []Record{
+		{Day: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), IssueKey: "PC-1", Duration: 2 * time.Hour},
+	}
+	worklogs := []SubmittedWorklog{
+		{Day: "2021-03-01", IssueKey: "PC-1", Seconds: 3600},
+		{Day: "2021-03-01", IssueKey: "PC-2", Seconds: 3600},
+	}
+
+	ApplySubmittedWorklogs(records, worklogs)
+
+	if records[0].Unsubmitted() != time.Hour {
+		t.Errorf("Unsubmitted returned %s, want 1h", records[0].Unsubmitted())
+	}
+}
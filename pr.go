@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/forge"
+	"got/pkg/git"
+	"got/pkg/jira"
+	"strings"
+)
+
+// createPullRequest pushes the current branch and creates pull request for it
+// with title and description taken from Jira issues of the branch
+func createPullRequest(ctx context.Context) {
+	currentBranchName, err := repository.CurrentBranch(ctx)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+	if currentBranchName == "" {
+		printErrorToConsole(errors.New("Pull request cannot be created for detached HEAD, switch to a branch"))
+		return
+	}
+
+	issueKeys := branchNamer.GetIssueKeysFromBranchName(currentBranchName)
+	if len(issueKeys) == 0 {
+		printErrorToConsole(fmt.Errorf(
+			"Branch name '%s' does not contain issue keys with prefix '%s'", currentBranchName, config.GetIssueKeyPrefix(),
+		))
+		return
+	}
+
+	foundIssues := fetchIssues(ctx, issueKeys)
+	var issues []jira.Issue
+	for _, issueKey := range issueKeys {
+		if issue, ok := foundIssues[issueKey]; ok {
+			issues = append(issues, issue)
+		}
+	}
+	if len(issues) == 0 {
+		return
+	}
+
+	remote, err := git.FindRemote(ctx, repository, config.Options.Git.Remote)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	if !config.Options.PullRequest.NoPush {
+		printInfoToConsole(fmt.Sprintf("Pushing branch '%s' to '%s'", currentBranchName, remote))
+		output, err := git.PushBranch(ctx, remote, currentBranchName)
+		if err != nil {
+			printErrorToConsole(err)
+			printInfoToConsole(strings.TrimSpace(string(output)))
+			return
+		}
+	}
+
+	forgeClient, err := newForgeClient(ctx, remote)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	targetBranch := strings.TrimPrefix(config.Options.PullRequest.BaseBranch, remote+"/")
+	if targetBranch == "" {
+		targetBranch, err = forgeClient.DefaultBranch(ctx)
+		if err != nil {
+			printErrorToConsole(err)
+			return
+		}
+	}
+
+	pullRequest := forge.PullRequest{
+		Title:        getPullRequestTitle(issues),
+		Body:         getPullRequestBody(issues, jiraClient.GetIssueURL),
+		SourceBranch: currentBranchName,
+		TargetBranch: targetBranch,
+	}
+	createdPullRequest, err := forgeClient.CreatePullRequest(ctx, pullRequest)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	printInfoToConsole(fmt.Sprintf(
+		"Created pull request #%d into '%s': %s", createdPullRequest.Number, targetBranch, createdPullRequest.URL,
+	))
+
+	var linkedIssueKeys []string
+	for _, issue := range issues {
+		linkedIssueKeys = append(linkedIssueKeys, issue.Key)
+	}
+	tasks, tasksCtx := newTaskGroup(ctx)
+	tasks.Go(func() error {
+		return setIssuesRemoteLink(tasksCtx, linkedIssueKeys, getBranchRemoteLink(forgeClient, currentBranchName))
+	})
+	tasks.Go(func() error {
+		remoteLink := getPullRequestRemoteLink(forgeClient.Type(), pullRequest, createdPullRequest)
+		return setIssuesRemoteLink(tasksCtx, linkedIssueKeys, remoteLink)
+	})
+	if err := tasks.Wait(); err != nil {
+		printErrorToConsole(err)
+	}
+}
+
+// newForgeClient returns client of git hosting service of the remote configured with options of the remote host
+func newForgeClient(ctx context.Context, remote string) (forge.Forge, error) {
+	remoteURL, err := repository.RemoteURL(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	repositoryURL, err := git.ParseRepositoryURL(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	hostOptions := config.GetForgeHostOptions(repositoryURL.Host)
+	forgeType := hostOptions.Type
+	if forgeType == "" {
+		forgeType = config.Options.Forge.Type
+	}
+	return forge.New(forge.Settings{
+		Type:       forge.Type(strings.ToLower(forgeType)),
+		APIURL:     hostOptions.APIURL,
+		WebURL:     hostOptions.WebURL,
+		Host:       repositoryURL.Host,
+		Owner:      repositoryURL.Owner,
+		Repository: repositoryURL.Name,
+		Token:      hostOptions.Token,
+		Username:   hostOptions.Username,
+		Timeout:    config.Options.Timeout.Duration,
+	})
+}
+
+// getPullRequestTitle returns title with issue keys and summary of the first issue, e.g. 'PC-123: Fix login'
+func getPullRequestTitle(issues []jira.Issue) string {
+	var issueKeys []string
+	for _, issue := range issues {
+		issueKeys = append(issueKeys, issue.Key)
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(issueKeys, ", "), issues[0].Fields.Summary)
+}
+
+// getPullRequestBody returns Markdown descriptions of the issues followed by links to them.
+// Descriptions of several issues are separated with headings of issue summaries.
+func getPullRequestBody(issues []jira.Issue, getIssueURL func(issueKey string) string) string {
+	var descriptions []string
+	for _, issue := range issues {
+		description := issue.GetMarkdownDescription()
+		if len(issues) > 1 {
+			description = strings.TrimSpace(fmt.Sprintf("## %s: %s\n\n%s", issue.Key, issue.Fields.Summary, description))
+		}
+		if description != "" {
+			descriptions = append(descriptions, description)
+		}
+	}
+
+	var links []string
+	for _, issue := range issues {
+		links = append(links, fmt.Sprintf("Jira: [%s](%s) %s", issue.Key, getIssueURL(issue.Key), issue.Fields.Summary))
+	}
+	if len(descriptions) == 0 {
+		return strings.Join(links, "\\\n")
+	}
+	return strings.Join(descriptions, "\n\n") + "\n\n---\n\n" + strings.Join(links, "\\\n")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"got/pkg/jira"
+	"testing"
+)
+
+func TestGetPullRequestTitleAndBody(t *testing.T) {
+	getIssueURL := func(issueKey string) string {
+		return "https://example.atlassian.net/browse/" + issueKey
+	}
+	description := jira.MarkdownToADF("Users can not **log in**")
+
+	first := jira.Issue{Key: "PC-1"}
+	first.Fields.Summary = "Fix login"
+	first.Fields.Description = &description
+	second := jira.Issue{Key: "PC-2"}
+	second.Fields.Summary = "Add tests"
+
+	if title := getPullRequestTitle([]jira.Issue{first}); title != "PC-1: Fix login" {
+		t.Errorf("getPullRequestTitle returned %q, want 'PC-1: Fix login'", title)
+	}
+	if title := getPullRequestTitle([]jira.Issue{first, second}); title != "PC-1, PC-2: Fix login" {
+		t.Errorf("getPullRequestTitle for two issues returned %q, want 'PC-1, PC-2: Fix login'", title)
+	}
+
+	tests := []struct {
+		issues []jira.Issue
+		want   string
+	}{
+		{
+			[]jira.Issue{first},
+			"Users can not **log in**\n\n---\n\nJira: [PC-1](https://example.atlassian.net/browse/PC-1) Fix login",
+		},
+		{
+			[]jira.Issue{second},
+			"Jira: [PC-2](https://example.atlassian.net/browse/PC-2) Add tests",
+		},
+		{
+			[]jira.Issue{first, second},
+			"## PC-1: Fix login\n\nUsers can not **log in**\n\n## PC-2: Add tests\n\n---\n\n" +
+				"Jira: [PC-1](https://example.atlassian.net/browse/PC-1) Fix login\\\n" +
+				"Jira: [PC-2](https://example.atlassian.net/browse/PC-2) Add tests",
+		},
+	}
+
+	for _, tt := range tests {
+		if body := getPullRequestBody(tt.issues, getIssueURL); body != tt.want {
+			t.Errorf("getPullRequestBody returned\n%s\nwant\n%s", body, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/git"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// issueBranch is a local or remote-tracking branch linked to Jira issues
+type issueBranch struct {
+	Name      string
+	Remote    string
+	IssueKeys []string
+	Merged    bool
+}
+
+func pruneBranches(ctx context.Context) {
+	baseBranch, err := getComparisonBaseBranch(ctx, config.Options.Prune.BaseBranch)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+	fetchBaseBranch(ctx, baseBranch)
+
+	branches, err := listIssueBranches(ctx, baseBranch, false)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	if config.Options.Prune.Remote {
+		remoteBranches, err := listIssueBranches(ctx, baseBranch, true)
+		if err != nil {
+			printErrorToConsole(err)
+			return
+		}
+		branches = append(branches, remoteBranches...)
+	}
+
+	if len(branches) == 0 {
+		printInfoToConsole("There are no branches linked to Jira issues")
+		return
+	}
+
+	var issueKeys []string
+	for _, branch := range branches {
+		issueKeys = append(issueKeys, branch.IssueKeys...)
+	}
+	issues := fetchIssues(ctx, issueKeys)
+
+	currentBranchName, err := repository.CurrentBranch(ctx)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	var branchesToDelete []issueBranch
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "BRANCH\tISSUES\tMERGED\tACTION")
+	for _, branch := range branches {
+		resolved := true
+		var statuses []string
+		for _, issueKey := range branch.IssueKeys {
+			issue, ok := issues[issueKey]
+			if !ok {
+				statuses = append(statuses, fmt.Sprintf("%s (unknown)", issueKey))
+				resolved = false
+				continue
+			}
+			statuses = append(statuses, fmt.Sprintf("%s (%s)", issueKey, issue.Fields.Status.Name))
+			resolved = resolved && issue.IsDone()
+		}
+
+		action := "delete"
+		switch {
+		case branch.Remote == "" && branch.Name == currentBranchName:
+			action = "keep: current branch"
+		case !branch.Merged:
+			action = "keep: not merged"
+		case !resolved:
+			action = "keep: issue not done"
+		default:
+			branchesToDelete = append(branchesToDelete, branch)
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%t\t%s\n", branch.Name, strings.Join(statuses, ", "), branch.Merged, action)
+	}
+	writer.Flush()
+
+	if len(branchesToDelete) == 0 {
+		printInfoToConsole("There are no branches to delete")
+		return
+	}
+
+	if config.Options.Prune.DryRun {
+		return
+	}
+
+	confirmed, err := askForConfirmation(ctx, fmt.Sprintf("Delete %d branches?", len(branchesToDelete)))
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+	if !confirmed {
+		return
+	}
+
+	for _, branch := range branchesToDelete {
+		var output []byte
+		if branch.Remote == "" {
+			output, err = git.DeleteBranch(ctx, branch.Name)
+		} else {
+			output, err = git.DeleteRemoteBranch(ctx, branch.Remote, strings.TrimPrefix(branch.Name, branch.Remote+"/"))
+		}
+		if err != nil {
+			printErrorToConsole(err)
+		}
+		printInfoToConsole(strings.TrimSpace(string(output)))
+	}
+}
+
+// listIssueBranches returns local or remote-tracking branches that contain issue keys
+func listIssueBranches(ctx context.Context, baseBranch string, remote bool) ([]issueBranch, error) {
+	branchNames, err := repository.ListBranches(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	mergedBranchNames, err := git.ListMergedBranches(ctx, baseBranch, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	var remotes []string
+	if remote {
+		remotes, err = git.GetRemotes(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var branches []issueBranch
+	for _, branchName := range branchNames {
+		issueKeys := branchNamer.GetIssueKeysFromBranchName(branchName)
+		if len(issueKeys) == 0 || branchName == baseBranch {
+			continue
+		}
+
+		branch := issueBranch{
+			Name:      branchName,
+			IssueKeys: issueKeys,
+			Merged:    stringInSlice(branchName, mergedBranchNames),
+		}
+		for _, remoteName := range remotes {
+			if strings.HasPrefix(branchName, remoteName+"/") {
+				branch.Remote = remoteName
+			}
+		}
+		branches = append(branches, branch)
+	}
+
+	return branches, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/forge"
+	"got/pkg/git"
+	"got/pkg/jira"
+	"strings"
+)
+
+// Prefixes of global ids of remote links added by got, Jira updates links with the same global id
+// instead of adding them again, so ids are derived from URLs of branches and pull requests
+const (
+	branchRemoteLinkPrefix      = "got:branch:"
+	pullRequestRemoteLinkPrefix = "got:pull-request:"
+)
+
+// linkIssuesToBranch adds links to the branch page to Jira issues. Empty remote means the remote chosen with
+// git.FindRemote. Repositories on hosts not recognised as GitHub, GitLab or Bitbucket are skipped.
+func linkIssuesToBranch(ctx context.Context, remote string, branchName string, issueKeys []string) error {
+	if config.Options.Jira.DisableRemoteLinks {
+		return nil
+	}
+
+	var err error
+	if remote == "" {
+		remote, err = git.FindRemote(ctx, repository, config.Options.Git.Remote)
+		if err != nil {
+			return err
+		}
+	}
+
+	forgeClient, err := newForgeClient(ctx, remote)
+	if errors.Is(err, forge.ErrUnknownHost) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return setIssuesRemoteLink(ctx, issueKeys, getBranchRemoteLink(forgeClient, branchName))
+}
+
+// setIssuesRemoteLink adds the link to Jira issues or updates the link added before.
+// Every issue is linked even when linking of some of them fails, errors are returned together.
+func setIssuesRemoteLink(ctx context.Context, issueKeys []string, remoteLink jira.RemoteLink) error {
+	if config.Options.Jira.DisableRemoteLinks {
+		return nil
+	}
+
+	var linkedIssueKeys []string
+	var errs taskErrors
+	for _, issueKey := range issueKeys {
+		if err := jiraClient.SetIssueRemoteLink(ctx, issueKey, remoteLink); err != nil {
+			errs = append(errs, fmt.Errorf("Failed to link %s to Jira issue %s: %w", remoteLink.Relationship, issueKey, err))
+			continue
+		}
+		linkedIssueKeys = append(linkedIssueKeys, issueKey)
+	}
+	if len(linkedIssueKeys) > 0 {
+		printInfoToConsole(fmt.Sprintf(
+			"Linked %s to Jira issues: '%s'", remoteLink.Relationship, strings.Join(linkedIssueKeys, ", "),
+		))
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func getBranchRemoteLink(forgeClient forge.Forge, branchName string) jira.RemoteLink {
+	branchURL := forgeClient.BranchURL(branchName)
+	return jira.RemoteLink{
+		GlobalID:     branchRemoteLinkPrefix + branchURL,
+		Relationship: "branch",
+		Object:       jira.RemoteLinkObject{URL: branchURL, Title: branchName},
+	}
+}
+
+func getPullRequestRemoteLink(forgeType forge.Type, pullRequest forge.PullRequest, created forge.CreatedPullRequest) jira.RemoteLink {
+	relationship := "pull request"
+	if forgeType == forge.GitLab {
+		relationship = "merge request"
+	}
+
+	return jira.RemoteLink{
+		GlobalID:     pullRequestRemoteLinkPrefix + created.URL,
+		Relationship: relationship,
+		Object: jira.RemoteLinkObject{
+			URL:     created.URL,
+			Title:   fmt.Sprintf("#%d %s", created.Number, pullRequest.Title),
+			Summary: fmt.Sprintf("%s into %s", pullRequest.SourceBranch, pullRequest.TargetBranch),
+		},
+	}
+}
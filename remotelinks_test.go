@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"got/pkg/forge"
+	"got/pkg/jira"
+	"got/pkg/jira/jiratest"
+	"strings"
+	"testing"
+)
+
+func TestGetRemoteLinks(t *testing.T) {
+	forgeClient, err := forge.New(forge.Settings{Host: "gitlab.com", Owner: "group", Repository: "got"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	branchLink := getBranchRemoteLink(forgeClient, "PC-1/fix")
+	if branchLink.GlobalID != "got:branch:https://gitlab.com/group/got/-/tree/PC-1/fix" ||
+		branchLink.Object.URL != "https://gitlab.com/group/got/-/tree/PC-1/fix" || branchLink.Object.Title != "PC-1/fix" {
+		t.Errorf("getBranchRemoteLink returned %+v", branchLink)
+	}
+
+	pullRequest := forge.PullRequest{Title: "PC-1: Fix", SourceBranch: "PC-1/fix", TargetBranch: "main"}
+	created := forge.CreatedPullRequest{Number: 3, URL: "https://gitlab.com/group/got/-/merge_requests/3"}
+	pullRequestLink := getPullRequestRemoteLink(forgeClient.Type(), pullRequest, created)
+	if pullRequestLink.GlobalID != "got:pull-request:"+created.URL || pullRequestLink.Relationship != "merge request" ||
+		pullRequestLink.Object.Title != "#3 PC-1: Fix" || pullRequestLink.Object.Summary != "PC-1/fix into main" {
+		t.Errorf("getPullRequestRemoteLink returned %+v", pullRequestLink)
+	}
+}
+
+func TestSetIssuesRemoteLink_LinksAllIssues(t *testing.T) {
+	server := jiratest.NewServer("PC")
+	t.Cleanup(server.Close)
+	server.AddIssue(jiratest.Issue{Key: "PC-1"})
+	server.AddIssue(jiratest.Issue{Key: "PC-3"})
+
+	savedClient := jiraClient
+	t.Cleanup(func() { jiraClient = savedClient })
+	jiraClient = jira.NewClient(jira.Settings{APIEndpoint: server.APIEndpoint(), ProjectCode: "PC"})
+
+	remoteLink := jira.RemoteLink{
+		GlobalID:     "got:branch:https://github.com/owner/got/tree/PC-1/fix",
+		Relationship: "branch",
+		Object:       jira.RemoteLinkObject{URL: "https://github.com/owner/got/tree/PC-1/fix", Title: "PC-1/fix"},
+	}
+	err := setIssuesRemoteLink(context.Background(), []string{"PC-1", "PC-2", "PC-3"}, remoteLink)
+	if err == nil || !strings.Contains(err.Error(), "PC-2") {
+		t.Errorf("setIssuesRemoteLink returned error %+v, want error of PC-2", err)
+	}
+
+	for _, issueKey := range []string{"PC-1", "PC-3"} {
+		issue, _ := server.Issue(issueKey)
+		if len(issue.RemoteLinks) != 1 || issue.RemoteLinks[0].GlobalID != remoteLink.GlobalID {
+			t.Errorf("setIssuesRemoteLink added links %+v to %s", issue.RemoteLinks, issueKey)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// taskGroup runs tasks concurrently. The first failed task cancels context of the group,
+// Wait returns errors of all failed tasks.
+type taskGroup struct {
+	waitGroup sync.WaitGroup
+	mutex     sync.Mutex
+	errors    taskErrors
+	cancel    context.CancelFunc
+}
+
+// taskErrors is an error aggregating errors of failed tasks
+type taskErrors []error
+
+func (errs taskErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// newTaskGroup creates task group and context for its tasks derived from ctx
+func newTaskGroup(ctx context.Context) (*taskGroup, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &taskGroup{cancel: cancel}, ctx
+}
+
+// Go runs task in a new goroutine
+func (group *taskGroup) Go(task func() error) {
+	group.waitGroup.Add(1)
+	go func() {
+		defer group.waitGroup.Done()
+
+		if err := task(); err != nil {
+			group.mutex.Lock()
+			group.errors = append(group.errors, err)
+			group.mutex.Unlock()
+			group.cancel()
+		}
+	}()
+}
+
+// Stop cancels context of the group and waits until all tasks exit, errors of the tasks are discarded.
+// Used when the operation the tasks belong to has failed.
+func (group *taskGroup) Stop() {
+	group.cancel()
+	group.waitGroup.Wait()
+}
+
+// Wait blocks until all tasks are finished and returns their errors
+func (group *taskGroup) Wait() error {
+	group.waitGroup.Wait()
+	group.cancel()
+
+	if len(group.errors) == 0 {
+		return nil
+	}
+	return group.errors
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTaskGroup_WithoutErrors(t *testing.T) {
+	group, _ := newTaskGroup(context.Background())
+	results := make(chan int, 2)
+	group.Go(func() error { results <- 1; return nil })
+	group.Go(func() error { results <- 2; return nil })
+
+	if err := group.Wait(); err != nil {
+		t.Errorf("taskGroup.Wait returned error %+v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("taskGroup ran %d tasks, want 2", len(results))
+	}
+}
+
+func TestTaskGroup_AggregatesErrorsAndCancelsContext(t *testing.T) {
+	group, ctx := newTaskGroup(context.Background())
+	group.Go(func() error { return errors.New("first failed") })
+	group.Go(func() error {
+		select {
+		case <-ctx.Done():
+			return errors.New("second cancelled")
+		case <-time.After(time.Second):
+			return nil
+		}
+	})
+
+	err := group.Wait()
+	if err == nil {
+		t.Fatal("taskGroup.Wait returned no error")
+	}
+
+	expectedErrors := map[string]bool{"first failed\nsecond cancelled": true, "second cancelled\nfirst failed": true}
+	if !expectedErrors[err.Error()] {
+		t.Errorf("taskGroup.Wait returned %q, want errors of both tasks", err.Error())
+	}
+}
+
+func TestTaskGroup_Stop(t *testing.T) {
+	group, ctx := newTaskGroup(context.Background())
+	finished := make(chan bool, 1)
+	group.Go(func() error {
+		<-ctx.Done()
+		finished <- true
+		return ctx.Err()
+	})
+
+	group.Stop()
+	if len(finished) != 1 {
+		t.Error("taskGroup.Stop returned before the task exited")
+	}
+}
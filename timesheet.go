@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/timesheet"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+func printTimesheet(ctx context.Context) {
+	journal, err := timesheet.DefaultJournal()
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	entries, err := journal.ReadEntries()
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	worklogs, err := journal.ReadSubmittedWorklogs()
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	now := time.Now()
+	from := timesheet.StartOfDay(now).AddDate(0, 0, 1-config.Options.Timesheet.Days)
+	records := timesheet.Summarize(entries, from, now, timesheet.MaxSessionDuration, getFirstIssueKeyFromBranchName)
+	timesheet.ApplySubmittedWorklogs(records, worklogs)
+
+	if len(records) == 0 {
+		printInfoToConsole("No time tracked for the period. Use 'got hooks install' to track branch checkouts")
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "DAY\tISSUE\tTIME\tSUBMITTED")
+	for _, record := range records {
+		fmt.Fprintf(
+			writer, "%s\t%s\t%s\t%s\n",
+			record.DayString(), record.IssueKey, formatDuration(record.Duration), formatDuration(record.Submitted),
+		)
+	}
+	writer.Flush()
+
+	if !config.Options.Timesheet.Submit {
+		return
+	}
+
+	submitTimesheetRecords(ctx, journal, records)
+}
+
+func submitTimesheetRecords(ctx context.Context, journal timesheet.Journal, records []timesheet.Record) {
+	var recordsToSubmit []timesheet.Record
+	var totalTime time.Duration
+	for _, record := range records {
+		if record.Unsubmitted().Round(time.Minute) < time.Minute {
+			continue
+		}
+		recordsToSubmit = append(recordsToSubmit, record)
+		totalTime += record.Unsubmitted().Round(time.Minute)
+	}
+
+	if len(recordsToSubmit) == 0 {
+		printInfoToConsole("All tracked time is already submitted to Jira")
+		return
+	}
+
+	confirmed, err := askForConfirmation(
+		ctx, fmt.Sprintf("Submit %d worklogs with total time %s to Jira?", len(recordsToSubmit), formatDuration(totalTime)),
+	)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+	if !confirmed {
+		return
+	}
+
+	for _, record := range recordsToSubmit {
+		timeSpent := record.Unsubmitted().Round(time.Minute)
+		err := jiraClient.AddIssueWorklog(ctx, record.IssueKey, record.Started, timeSpent)
+		if err != nil {
+			printErrorToConsole(err)
+			continue
+		}
+
+		err = journal.AddSubmittedWorklog(timesheet.SubmittedWorklog{
+			Day:      record.DayString(),
+			IssueKey: record.IssueKey,
+			Seconds:  int(timeSpent.Seconds()),
+		})
+		if err != nil {
+			printErrorToConsole(err)
+			continue
+		}
+
+		printInfoToConsole(fmt.Sprintf("Logged %s to %s for %s", formatDuration(timeSpent), record.IssueKey, record.DayString()))
+	}
+}
+
+func getFirstIssueKeyFromBranchName(branchName string) string {
+	issueKeys := branchNamer.GetIssueKeysFromBranchName(branchName)
+	if len(issueKeys) == 0 {
+		return ""
+	}
+	return issueKeys[0]
+}
+
+func formatDuration(duration time.Duration) string {
+	minutes := int(duration.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
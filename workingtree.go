@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/git"
+	"strings"
+	"time"
+)
+
+// prepareWorkingTreeForCheckout handles local changes before switching branches
+// according to the configured strategy. Returns name of the branch which changes were stashed.
+func prepareWorkingTreeForCheckout(ctx context.Context) (string, error) {
+	if config.Options.DirtyWorkingTree == config.CarryDirtyWorkingTree {
+		return "", nil
+	}
+
+	changedFiles, err := repository.ChangedFiles(ctx)
+	if err != nil || len(changedFiles) == 0 {
+		return "", err
+	}
+
+	if config.Options.DirtyWorkingTree != config.StashDirtyWorkingTree {
+		return "", fmt.Errorf(
+			"Working tree has local changes:\n  %s\nCommit them or switch branches with -dirty=stash or -dirty=carry",
+			strings.Join(changedFiles, "\n  "),
+		)
+	}
+
+	currentBranchName, err := repository.CurrentBranch(ctx)
+	if err != nil {
+		return "", err
+	}
+	if currentBranchName == "" {
+		return "", errors.New("Local changes cannot be stashed automatically in detached HEAD state")
+	}
+
+	message := getAutoStashMessage(currentBranchName)
+	if issueKeys := branchNamer.GetIssueKeysFromBranchName(currentBranchName); len(issueKeys) > 0 {
+		message = fmt.Sprintf("%s [%s]", message, strings.Join(issueKeys, " "))
+	}
+	output, err := git.StashChanges(ctx, message)
+	if err != nil {
+		printInfoToConsole(string(output))
+		return "", err
+	}
+
+	printInfoToConsole(fmt.Sprintf("Local changes of branch '%s' were stashed", currentBranchName))
+	return currentBranchName, nil
+}
+
+// restoreStashTimeout limits restoring of the stashed changes, which runs even after the operation was interrupted
+const restoreStashTimeout = 10 * time.Second
+
+// restoreAutoStash applies local changes stashed when leaving the branch earlier.
+// The changes are restored even when ctx is cancelled by Ctrl-C, so they are not left in the stash.
+func restoreAutoStash(ctx context.Context, branchName string) {
+	// changes are stashed only by git binary
+	if branchName == "" || !git.IsGitInstalled() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(withoutCancel{ctx}, restoreStashTimeout)
+	defer cancel()
+
+	stashRef, err := git.FindStash(ctx, getAutoStashMessage(branchName))
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+	if stashRef == "" {
+		return
+	}
+
+	output, err := git.PopStash(ctx, stashRef)
+	if err != nil {
+		printErrorToConsole(err)
+		printInfoToConsole(string(output))
+		return
+	}
+
+	printInfoToConsole(fmt.Sprintf("Local changes of branch '%s' were restored from the stash", branchName))
+}
+
+// withoutCancel is a context keeping values of the parent context but never cancelled with it
+type withoutCancel struct {
+	parent context.Context
+}
+
+func (withoutCancel) Deadline() (time.Time, bool)           { return time.Time{}, false }
+func (withoutCancel) Done() <-chan struct{}                 { return nil }
+func (withoutCancel) Err() error                            { return nil }
+func (ctx withoutCancel) Value(key interface{}) interface{} { return ctx.parent.Value(key) }
+
+func getAutoStashMessage(branchName string) string {
+	return fmt.Sprintf("got autostash of '%s'", branchName)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"got/pkg/config"
+	"got/pkg/git"
+	"got/pkg/jira"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+func checkoutJiraWorktree(ctx context.Context, issue jira.Issue) {
+	worktrees, err := git.ListWorktrees(ctx)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	for _, worktree := range worktrees {
+		if stringInSlice(issue.Key, branchNamer.GetIssueKeysFromBranchName(worktree.Branch)) {
+			printInfoToConsole(fmt.Sprintf("Worktree of branch '%s' already exists, switch to it with:", worktree.Branch))
+			printInfoToConsole(fmt.Sprintf("cd %s", worktree.Path))
+			return
+		}
+	}
+
+	worktreesDirectory, err := getWorktreesDirectory(worktrees)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+	worktreePath := filepath.Join(worktreesDirectory, issue.Key)
+
+	branchName, err := git.FindBranchBySubstring(ctx, repository, issue.Key+config.Options.IssueBranchSeparator)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	var output []byte
+	if branchName != "" {
+		output, err = git.AddWorktree(ctx, worktreePath, branchName)
+		if err != nil {
+			printErrorToConsole(err)
+			printInfoToConsole(string(output))
+			return
+		}
+	} else {
+		tasks, tasksCtx := newTaskGroup(ctx)
+		tasks.Go(func() error {
+			return updateIssueForNewBranch(tasksCtx, issue.Key)
+		})
+
+		branchName, err = branchNamer.GenerateBranchName([]string{issue.Key}, issue.Fields.Summary)
+		if err != nil {
+			tasks.Stop()
+			printErrorToConsole(err)
+			return
+		}
+
+		baseBranch, err := resolveBaseBranch(ctx, issue.Fields.IssueType.Name)
+		if err != nil {
+			tasks.Stop()
+			printErrorToConsole(err)
+			return
+		}
+
+		output, err = git.AddWorktreeWithNewBranch(ctx, worktreePath, branchName, baseBranch)
+		if err != nil {
+			tasks.Stop()
+			printErrorToConsole(err)
+			printInfoToConsole(string(output))
+			return
+		}
+
+		// only branches created successfully are linked
+		tasks.Go(func() error {
+			return linkIssuesToBranch(tasksCtx, "", branchName, []string{issue.Key})
+		})
+		if tasksErr := tasks.Wait(); tasksErr != nil {
+			printErrorToConsole(tasksErr)
+		}
+	}
+
+	printInfoToConsole(strings.TrimSpace(string(output)))
+	printJiraIssueData(issue)
+	printInfoToConsole("Switch to the worktree with:")
+	printInfoToConsole(fmt.Sprintf("cd %s", worktreePath))
+}
+
+func manageWorktrees(ctx context.Context) {
+	if config.Options.PruneWorktrees {
+		output, err := git.PruneWorktrees(ctx)
+		printInfoToConsole(strings.TrimSpace(string(output)))
+		if err != nil {
+			printErrorToConsole(err)
+			return
+		}
+	}
+
+	worktrees, err := git.ListWorktrees(ctx)
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+
+	var issueKeys []string
+	for _, worktree := range worktrees {
+		issueKeys = append(issueKeys, branchNamer.GetIssueKeysFromBranchName(worktree.Branch)...)
+	}
+	issues := fetchIssues(ctx, issueKeys)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "PATH\tBRANCH\tISSUES")
+	var resolvedWorktrees []git.Worktree
+	for i, worktree := range worktrees {
+		issueKeys := branchNamer.GetIssueKeysFromBranchName(worktree.Branch)
+		var statuses []string
+		resolved := len(issueKeys) > 0
+		for _, issueKey := range issueKeys {
+			issue, ok := issues[issueKey]
+			if !ok {
+				statuses = append(statuses, fmt.Sprintf("%s (unknown)", issueKey))
+				resolved = false
+				continue
+			}
+			statuses = append(statuses, fmt.Sprintf("%s (%s)", issueKey, issue.Fields.Status.Name))
+			resolved = resolved && issue.IsDone()
+		}
+
+		branchName := worktree.Branch
+		if worktree.Detached {
+			branchName = "(detached)"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", worktree.Path, branchName, strings.Join(statuses, ", "))
+
+		if i > 0 && resolved {
+			resolvedWorktrees = append(resolvedWorktrees, worktree)
+		}
+	}
+	writer.Flush()
+
+	if config.Options.PruneWorktrees {
+		removeWorktrees(ctx, resolvedWorktrees)
+	}
+}
+
+func removeWorktrees(ctx context.Context, worktrees []git.Worktree) {
+	if len(worktrees) == 0 {
+		printInfoToConsole("There are no worktrees of resolved Jira issues")
+		return
+	}
+
+	printInfoToConsole("Worktrees of resolved Jira issues:")
+	for _, worktree := range worktrees {
+		printInfoToConsole(fmt.Sprintf("  %s", worktree.Path))
+	}
+
+	confirmed, err := askForConfirmation(ctx, fmt.Sprintf("Remove %d worktrees?", len(worktrees)))
+	if err != nil {
+		printErrorToConsole(err)
+		return
+	}
+	if !confirmed {
+		return
+	}
+
+	for _, worktree := range worktrees {
+		output, err := git.RemoveWorktree(ctx, worktree.Path)
+		if err != nil {
+			printErrorToConsole(err)
+			printInfoToConsole(strings.TrimSpace(string(output)))
+			continue
+		}
+		printInfoToConsole(fmt.Sprintf("Removed worktree '%s'", worktree.Path))
+	}
+}
+
+// getWorktreesDirectory returns configured worktrees directory resolved relatively to the main worktree,
+// by default worktrees are created next to the main worktree in '<repository>-worktrees' directory
+func getWorktreesDirectory(worktrees []git.Worktree) (string, error) {
+	if len(worktrees) == 0 {
+		return "", errors.New("Failed to find the main worktree of the repository")
+	}
+	mainWorktreePath := worktrees[0].Path
+
+	directory := config.Options.Worktree.Directory
+	if directory == "" {
+		directory = filepath.Join("..", filepath.Base(mainWorktreePath)+"-worktrees")
+	}
+
+	if filepath.IsAbs(directory) {
+		return directory, nil
+	}
+	return filepath.Join(mainWorktreePath, directory), nil
+}